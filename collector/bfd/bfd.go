@@ -0,0 +1,168 @@
+package bfd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/parsers"
+)
+
+var (
+	properties = []string{"remote-address", "local-address", "interface", "state", "state-changes", "uptime", "desired-tx-interval", "required-min-rx", "multiplier"}
+	// states - known session states, others are exported as unknown
+	states             = []string{"up", "down", "init", "admin-down", unknownState}
+	labelNames         = []string{"name", "address", "remote_address", "local_address", "interface"}
+	metricDescriptions = map[string]*metrics.MetricDescription{
+		"state-changes": {
+			Desc:      metrics.BuildMetricDescription(prefix, "state_changes", "number of bfd session state changes", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"uptime": {
+			Desc:      metrics.BuildMetricDescription(prefix, "uptime", "bfd session uptime in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"desired-tx-interval": {
+			Desc:      metrics.BuildMetricDescription(prefix, "desired_tx_interval", "desired bfd transmit interval in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"required-min-rx": {
+			Desc:      metrics.BuildMetricDescription(prefix, "required_min_rx", "required minimum bfd receive interval in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"multiplier": {
+			Desc:      metrics.BuildMetricDescription(prefix, "multiplier", "bfd detection time multiplier", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+	}
+	stateMetricDescription = metrics.BuildMetricDescription(prefix, "state", "bfd session state (current state = 1)", append(labelNames, "state"))
+)
+
+const (
+	prefix       = "bfd_session"
+	unknownState = "unknown"
+)
+
+type bfdCollector struct{}
+
+func NewCollector() *bfdCollector {
+	return &bfdCollector{}
+}
+
+func (c *bfdCollector) Name() string {
+	return prefix
+}
+
+func (c *bfdCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stateMetricDescription
+	for _, d := range metricDescriptions {
+		ch <- d.Desc
+	}
+}
+
+// Supported - the bfd session menu is only available on RouterOS v7
+func (c *bfdCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MajorVersion() != 6
+}
+
+func (c *bfdCollector) Collect(ctx *context.Context) error {
+	stats, err := c.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bfd sessions: %w", err)
+	}
+
+	for _, re := range stats {
+		c.collectForStat(re, ctx)
+	}
+
+	return nil
+}
+
+func (c *bfdCollector) fetch(ctx *context.Context) ([]*proto.Sentence, error) {
+	reply, err := ctx.RouterOSClient.Run(
+		"/routing/bfd/session/print",
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.Re, nil
+}
+
+func (c *bfdCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, re.Map["remote-address"], re.Map["local-address"], re.Map["interface"]}
+
+	if value := re.Map["state"]; len(value) != 0 {
+		if !isKnownState(value) {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"remote":    re.Map["remote-address"],
+				"state":     value,
+			}).Warn("unexpected bfd session state, exporting it as unknown")
+			value = unknownState
+		}
+
+		for _, s := range states {
+			var v float64
+			if s == value {
+				v = 1
+			}
+
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(stateMetricDescription, prometheus.GaugeValue, v, append(labelValues, s)...)
+		}
+	}
+
+	for p := range metricDescriptions {
+		c.collectMetricForProperty(p, re, labelValues, ctx)
+	}
+}
+
+func isKnownState(state string) bool {
+	for _, s := range states {
+		if s == state && s != unknownState {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *bfdCollector) collectMetricForProperty(property string, re *proto.Sentence, labelValues []string, ctx *context.Context) {
+	value := re.Map[property]
+	if len(value) == 0 {
+		return
+	}
+
+	var (
+		v   float64
+		err error
+	)
+	switch property {
+	case "uptime", "desired-tx-interval", "required-min-rx":
+		v, err = parsers.ParseDuration(value)
+	default:
+		v, err = strconv.ParseFloat(value, 64)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector": c.Name(),
+			"device":    ctx.DeviceName,
+			"remote":    re.Map["remote-address"],
+			"property":  property,
+			"value":     value,
+			"error":     err,
+		}).Error("failed to parse bfd metric value")
+		return
+	}
+
+	desc := metricDescriptions[property]
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, v, labelValues...)
+}
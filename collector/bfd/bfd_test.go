@@ -0,0 +1,231 @@
+package bfd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_bfdCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("bfd_session", c.Name())
+}
+
+func Test_bfdCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "state", "bfd session state (current state = 1)",
+			[]string{"name", "address", "remote_address", "local_address", "interface", "state"},
+		),
+		metrics.BuildMetricDescription(prefix, "state_changes", "number of bfd session state changes", labelNames),
+		metrics.BuildMetricDescription(prefix, "uptime", "bfd session uptime in seconds", labelNames),
+		metrics.BuildMetricDescription(prefix, "desired_tx_interval", "desired bfd transmit interval in seconds", labelNames),
+		metrics.BuildMetricDescription(prefix, "required_min_rx", "required minimum bfd receive interval in seconds", labelNames),
+		metrics.BuildMetricDescription(prefix, "multiplier", "bfd detection time multiplier", labelNames),
+	}, got)
+}
+
+func Test_bfdCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Version: "7.12.1 (stable)"}))
+	r.False(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)"}))
+}
+
+func Test_bfdCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	sentence := []string{
+		"/routing/bfd/session/print",
+		"=.proplist=remote-address,local-address,interface,state,state-changes,uptime,desired-tx-interval,required-min-rx,multiplier",
+	}
+	stateDesc := metrics.BuildMetricDescription(prefix, "state", "bfd session state (current state = 1)",
+		[]string{"name", "address", "remote_address", "local_address", "interface", "state"},
+	)
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(sentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"remote-address":      "10.0.0.2",
+								"local-address":       "10.0.0.1",
+								"interface":           "ether1",
+								"state":               "up",
+								"state-changes":       "3",
+								"uptime":              "1h2m3s",
+								"desired-tx-interval": "200ms",
+								"required-min-rx":     "200ms",
+								"multiplier":          "5",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, "device", "address", "10.0.0.2", "10.0.0.1", "ether1", "up"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "10.0.0.1", "ether1", "down"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "10.0.0.1", "ether1", "init"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "10.0.0.1", "ether1", "admin-down"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "10.0.0.1", "ether1", "unknown"),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "state_changes", "number of bfd session state changes", labelNames),
+					prometheus.CounterValue, 3, "device", "address", "10.0.0.2", "10.0.0.1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "uptime", "bfd session uptime in seconds", labelNames),
+					prometheus.GaugeValue, 3723, "device", "address", "10.0.0.2", "10.0.0.1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "desired_tx_interval", "desired bfd transmit interval in seconds", labelNames),
+					prometheus.GaugeValue, 0.2, "device", "address", "10.0.0.2", "10.0.0.1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "required_min_rx", "required minimum bfd receive interval in seconds", labelNames),
+					prometheus.GaugeValue, 0.2, "device", "address", "10.0.0.2", "10.0.0.1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "multiplier", "bfd detection time multiplier", labelNames),
+					prometheus.GaugeValue, 5, "device", "address", "10.0.0.2", "10.0.0.1", "ether1",
+				),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(sentence...).Then(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch bfd sessions: some fetch error",
+		},
+		{
+			name: "unexpected state",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(sentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"remote-address": "10.0.0.3",
+								"state":          "fail",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.3", "", "", "up"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.3", "", "", "down"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.3", "", "", "init"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.3", "", "", "admin-down"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, "device", "address", "10.0.0.3", "", "", "unknown"),
+			},
+		},
+		{
+			name: "parse error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(sentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"remote-address": "10.0.0.2",
+								"state":          "init",
+								"state-changes":  "x",
+								"multiplier":     "3",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "", "", "up"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "", "", "down"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, "device", "address", "10.0.0.2", "", "", "init"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "", "", "admin-down"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "10.0.0.2", "", "", "unknown"),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "multiplier", "bfd detection time multiplier", labelNames),
+					prometheus.GaugeValue, 3, "device", "address", "10.0.0.2", "", "",
+				),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
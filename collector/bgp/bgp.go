@@ -2,19 +2,37 @@ package bgp
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
 	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/parsers"
 )
 
 var (
-	properties         = []string{"name", "remote.as", "remote.address", "established", "remote.messages", "local.messages", "remote.bytes", "local.bytes"}
+	properties           = []string{"name", "remote.as", "remote.address", "established", "remote.messages", "local.messages", "remote.bytes", "local.bytes", "prefix-count", "uptime", "state", "last-started", "last-stopped", "remote.hold-time", "local.role"}
+	connectionProperties = []string{"name", "templates", "vrf"}
+	// peerProperties - RouterOS v6 /routing/bgp/peer properties mapped to their v7 session counterparts
+	peerProperties = map[string]string{
+		"name":             "name",
+		"remote-as":        "remote.as",
+		"remote-address":   "remote.address",
+		"established":      "established",
+		"updates-received": "remote.messages",
+		"updates-sent":     "local.messages",
+		"prefix-count":     "prefix-count",
+		"uptime":           "uptime",
+		"state":            "state",
+		"remote-hold-time": "remote.hold-time",
+	}
 	labelNames         = []string{"name", "address", "session", "asn", "remote_address"}
 	metricDescriptions = map[string]*metrics.MetricDescription{
 		"established": {
@@ -37,7 +55,31 @@ var (
 			Desc:      metrics.BuildMetricDescription(prefix, "local_bytes", "number of bytes sent per session", labelNames),
 			ValueType: prometheus.CounterValue,
 		},
+		"prefix-count": {
+			Desc:      metrics.BuildMetricDescription(prefix, "prefix_count", "number of prefixes received per session", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"uptime": {
+			Desc:      metrics.BuildMetricDescription(prefix, "uptime", "bgp session uptime in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"remote.hold-time": {
+			Desc:      metrics.BuildMetricDescription(prefix, "remote_hold_time", "hold time announced by the remote peer in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"last-started": {
+			Desc:      metrics.BuildMetricDescription(prefix, "last_started_time", "last time the session was started as unix timestamp", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"last-stopped": {
+			Desc:      metrics.BuildMetricDescription(prefix, "last_stopped_time", "last time the session was stopped as unix timestamp", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
 	}
+	// infoMetricDescription - carries session state and connection config, join it on session to filter other metrics
+	infoMetricDescription = metrics.BuildMetricDescription(prefix, "info", "bgp session state, local role and connection config",
+		[]string{"name", "address", "session", "asn", "remote_address", "state", "local_role", "connection", "templates", "vrf"},
+	)
 )
 
 const prefix = "bgp_session"
@@ -53,19 +95,78 @@ func (c *bgpCollector) Name() string {
 }
 
 func (c *bgpCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- infoMetricDescription
 	for _, d := range metricDescriptions {
 		ch <- d.Desc
 	}
 }
 
 func (c *bgpCollector) Collect(ctx *context.Context) error {
-	stats, err := c.fetch(ctx)
+	// unknown versions are handled as v7
+	if v := ctx.Capabilities.MajorVersion(); v != 0 && v < 7 {
+		return c.collectPeers(ctx)
+	}
+
+	return c.collectSessions(ctx)
+}
+
+func (c *bgpCollector) collectSessions(ctx *context.Context) error {
+	var (
+		stats       []*proto.Sentence
+		connections []*proto.Sentence
+	)
+
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		var err error
+		if stats, err = c.fetch(ctx); err != nil {
+			return fmt.Errorf("failed to fetch bgp metrics: %w", err)
+		}
+
+		return nil
+	})
+
+	eg.Go(func() error {
+		var err error
+		if connections, err = c.fetchConnections(ctx); err != nil {
+			return fmt.Errorf("failed to fetch bgp connections: %w", err)
+		}
+
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	connectionsByName := make(map[string]*proto.Sentence, len(connections))
+	for _, re := range connections {
+		connectionsByName[re.Map["name"]] = re
+	}
+
+	for _, re := range stats {
+		c.collectForStat(re, findConnection(re.Map["name"], connectionsByName), ctx)
+	}
+
+	return nil
+}
+
+// collectPeers - collects RouterOS v6 peers under the same metric names as v7 sessions
+func (c *bgpCollector) collectPeers(ctx *context.Context) error {
+	stats, err := c.fetchPeers(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch bgp metrics: %w", err)
 	}
 
 	for _, re := range stats {
-		c.collectForStat(re, ctx)
+		session := &proto.Sentence{Map: make(map[string]string, len(peerProperties))}
+		for p, sessionProperty := range peerProperties {
+			if v, ok := re.Map[p]; ok {
+				session.Map[sessionProperty] = v
+			}
+		}
+
+		c.collectForStat(session, nil, ctx)
 	}
 
 	return nil
@@ -83,12 +184,77 @@ func (c *bgpCollector) fetch(ctx *context.Context) ([]*proto.Sentence, error) {
 	return reply.Re, nil
 }
 
-func (c *bgpCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
+func (c *bgpCollector) fetchConnections(ctx *context.Context) ([]*proto.Sentence, error) {
+	reply, err := ctx.RouterOSClient.Run(
+		"/routing/bgp/connection/print",
+		"=.proplist="+strings.Join(connectionProperties, ","),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.Re, nil
+}
+
+func (c *bgpCollector) fetchPeers(ctx *context.Context) ([]*proto.Sentence, error) {
+	properties := make([]string, 0, len(peerProperties))
+	for p := range peerProperties {
+		properties = append(properties, p)
+	}
+	sort.Strings(properties)
+
+	reply, err := ctx.RouterOSClient.Run(
+		"/routing/bgp/peer/print",
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.Re, nil
+}
+
+// findConnection - sessions are named after their connection with a "-<n>" suffix, e.g. peer1-1 for connection peer1
+func findConnection(session string, connections map[string]*proto.Sentence) *proto.Sentence {
+	if re, ok := connections[session]; ok {
+		return re
+	}
+
+	i := strings.LastIndex(session, "-")
+	if i <= 0 {
+		return nil
+	}
+
+	// only the numeric session suffix is stripped, e.g. peer1-backup doesn't belong to connection peer1
+	if _, err := strconv.Atoi(session[i+1:]); err != nil {
+		return nil
+	}
+
+	return connections[session[:i]]
+}
+
+func (c *bgpCollector) collectForStat(re, connection *proto.Sentence, ctx *context.Context) {
+	c.collectInfo(re, connection, ctx)
+
 	for p := range metricDescriptions {
 		c.collectMetricForProperty(p, re, ctx)
 	}
 }
 
+func (c *bgpCollector) collectInfo(re, connection *proto.Sentence, ctx *context.Context) {
+	var connectionName, templates, vrf string
+	if connection != nil {
+		connectionName = connection.Map["name"]
+		templates = connection.Map["templates"]
+		vrf = connection.Map["vrf"]
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(infoMetricDescription, prometheus.GaugeValue, 1,
+		ctx.DeviceName, ctx.DeviceAddress, re.Map["name"], re.Map["remote.as"], re.Map["remote.address"],
+		re.Map["state"], re.Map["local.role"], connectionName, templates, vrf,
+	)
+}
+
 func (c *bgpCollector) collectMetricForProperty(property string, re *proto.Sentence, ctx *context.Context) {
 	value := re.Map[property]
 	if len(value) == 0 {
@@ -106,6 +272,13 @@ func (c *bgpCollector) collectMetricForProperty(property string, re *proto.Sente
 		if value == "true" {
 			v = 1
 		}
+	case "uptime", "remote.hold-time":
+		v, err = parsers.ParseDuration(value)
+	case "last-started", "last-stopped":
+		var t time.Time
+		if t, err = parsers.ParseDatetimeInLocation(value, ctx.Capabilities.Location()); err == nil {
+			v = float64(t.Unix())
+		}
 	default:
 		v, err = strconv.ParseFloat(value, 64)
 	}
@@ -42,11 +42,19 @@ func Test_bgpCollector_Describe(t *testing.T) {
 
 	<-done
 	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "info", "bgp session state, local role and connection config",
+			[]string{"name", "address", "session", "asn", "remote_address", "state", "local_role", "connection", "templates", "vrf"},
+		),
 		metrics.BuildMetricDescription(prefix, "established", "bgp session established (up = 1)", labelNames),
 		metrics.BuildMetricDescription(prefix, "remote_messages", "number of bgp messages received per session", labelNames),
 		metrics.BuildMetricDescription(prefix, "local_messages", "number of bgp messages sent per session", labelNames),
 		metrics.BuildMetricDescription(prefix, "remote_bytes", "number of bytes received per session", labelNames),
 		metrics.BuildMetricDescription(prefix, "local_bytes", "number of bytes sent per session", labelNames),
+		metrics.BuildMetricDescription(prefix, "prefix_count", "number of prefixes received per session", labelNames),
+		metrics.BuildMetricDescription(prefix, "uptime", "bgp session uptime in seconds", labelNames),
+		metrics.BuildMetricDescription(prefix, "remote_hold_time", "hold time announced by the remote peer in seconds", labelNames),
+		metrics.BuildMetricDescription(prefix, "last_started_time", "last time the session was started as unix timestamp", labelNames),
+		metrics.BuildMetricDescription(prefix, "last_stopped_time", "last time the session was stopped as unix timestamp", labelNames),
 	}, got)
 }
 
@@ -60,81 +68,202 @@ func Test_bgpCollector_Collect(t *testing.T) {
 		routerOSClientMock = mocks.NewClientMock(t)
 	}
 
+	sessionSentence := []string{
+		"/routing/bgp/session/print",
+		"=.proplist=name,remote.as,remote.address,established,remote.messages,local.messages,remote.bytes,local.bytes,prefix-count,uptime,state,last-started,last-stopped,remote.hold-time,local.role",
+	}
+	connectionSentence := []string{
+		"/routing/bgp/connection/print",
+		"=.proplist=name,templates,vrf",
+	}
+	infoDesc := metrics.BuildMetricDescription(prefix, "info", "bgp session state, local role and connection config",
+		[]string{"name", "address", "session", "asn", "remote_address", "state", "local_role", "connection", "templates", "vrf"},
+	)
+
 	testCases := []struct {
-		name     string
-		setMocks func()
-		want     []prometheus.Metric
-		errWant  string
+		name         string
+		capabilities *context.Capabilities
+		setMocks     func()
+		want         []prometheus.Metric
+		errWant      string
 	}{
 		{
-			name: "success",
+			name:         "success",
+			capabilities: &context.Capabilities{Version: "7.12.1 (stable)"},
 			setMocks: func() {
-				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
-					r.Equal([]string{
-						"/routing/bgp/session/print",
-						"=.proplist=name,remote.as,remote.address,established,remote.messages,local.messages,remote.bytes,local.bytes",
-					}, sentence)
-				}).Return(&routeros.Reply{
+				routerOSClientMock.RunMock.When(sessionSentence...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
 						{
 							Map: map[string]string{
-								"name":            "session",
-								"remote.as":       "65000",
-								"remote.address":  "1.1.1.1",
-								"established":     "true",
-								"remote.messages": "111",
-								"local.messages":  "11",
-								"remote.bytes":    "222",
-								"local.bytes":     "21",
+								"name":             "peer1-1",
+								"remote.as":        "65000",
+								"remote.address":   "1.1.1.1",
+								"established":      "true",
+								"remote.messages":  "111",
+								"local.messages":   "11",
+								"remote.bytes":     "222",
+								"local.bytes":      "21",
+								"prefix-count":     "0",
+								"uptime":           "1h2m3s",
+								"state":            "established",
+								"last-started":     "2023-05-16 10:17:34",
+								"remote.hold-time": "3m",
+								"local.role":       "ebgp",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":           "session",
+								"remote.as":      "65001",
+								"remote.address": "2.2.2.2",
+								"established":    "false",
+								"state":          "idle",
+								"last-stopped":   "may/16/2023 10:17:34",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When(connectionSentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":      "peer1",
+								"templates": "default",
+								"vrf":       "customers",
 							},
 						},
 					},
 				}, nil)
 			},
 			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "peer1-1", "65000", "1.1.1.1", "established", "ebgp", "peer1", "default", "customers",
+				),
 				prometheus.MustNewConstMetric(
 					metrics.BuildMetricDescription(prefix, "established", "bgp session established (up = 1)", labelNames),
-					prometheus.GaugeValue, 1, "device", "address", "session", "65000", "1.1.1.1",
+					prometheus.GaugeValue, 1, "device", "address", "peer1-1", "65000", "1.1.1.1",
 				),
 				prometheus.MustNewConstMetric(
 					metrics.BuildMetricDescription(prefix, "remote_messages", "number of bgp messages received per session", labelNames),
-					prometheus.CounterValue, 111, "device", "address", "session", "65000", "1.1.1.1",
+					prometheus.CounterValue, 111, "device", "address", "peer1-1", "65000", "1.1.1.1",
 				),
 				prometheus.MustNewConstMetric(
 					metrics.BuildMetricDescription(prefix, "local_messages", "number of bgp messages sent per session", labelNames),
-					prometheus.CounterValue, 11, "device", "address", "session", "65000", "1.1.1.1",
+					prometheus.CounterValue, 11, "device", "address", "peer1-1", "65000", "1.1.1.1",
 				),
 				prometheus.MustNewConstMetric(
 					metrics.BuildMetricDescription(prefix, "remote_bytes", "number of bytes received per session", labelNames),
-					prometheus.CounterValue, 222, "device", "address", "session", "65000", "1.1.1.1",
+					prometheus.CounterValue, 222, "device", "address", "peer1-1", "65000", "1.1.1.1",
 				),
 				prometheus.MustNewConstMetric(
 					metrics.BuildMetricDescription(prefix, "local_bytes", "number of bytes sent per session", labelNames),
-					prometheus.CounterValue, 21, "device", "address", "session", "65000", "1.1.1.1",
+					prometheus.CounterValue, 21, "device", "address", "peer1-1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "prefix_count", "number of prefixes received per session", labelNames),
+					prometheus.GaugeValue, 0, "device", "address", "peer1-1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "uptime", "bgp session uptime in seconds", labelNames),
+					prometheus.GaugeValue, 3723, "device", "address", "peer1-1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "remote_hold_time", "hold time announced by the remote peer in seconds", labelNames),
+					prometheus.GaugeValue, 180, "device", "address", "peer1-1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "last_started_time", "last time the session was started as unix timestamp", labelNames),
+					prometheus.GaugeValue, 1684232254, "device", "address", "peer1-1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "session", "65001", "2.2.2.2", "idle", "", "", "", "",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "established", "bgp session established (up = 1)", labelNames),
+					prometheus.GaugeValue, 0, "device", "address", "session", "65001", "2.2.2.2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "last_stopped_time", "last time the session was stopped as unix timestamp", labelNames),
+					prometheus.GaugeValue, 1684232254, "device", "address", "session", "65001", "2.2.2.2",
+				),
+			},
+		},
+		{
+			name:         "success v6",
+			capabilities: &context.Capabilities{Version: "6.49.10 (long-term)"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/routing/bgp/peer/print",
+					"=.proplist=established,name,prefix-count,remote-address,remote-as,remote-hold-time,state,updates-received,updates-sent,uptime",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":             "peer1",
+								"remote-as":        "65000",
+								"remote-address":   "1.1.1.1",
+								"established":      "true",
+								"updates-received": "111",
+								"updates-sent":     "11",
+								"prefix-count":     "42",
+								"uptime":           "1h2m3s",
+								"state":            "established",
+								"remote-hold-time": "3m",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "peer1", "65000", "1.1.1.1", "established", "", "", "", "",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "established", "bgp session established (up = 1)", labelNames),
+					prometheus.GaugeValue, 1, "device", "address", "peer1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "remote_messages", "number of bgp messages received per session", labelNames),
+					prometheus.CounterValue, 111, "device", "address", "peer1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "local_messages", "number of bgp messages sent per session", labelNames),
+					prometheus.CounterValue, 11, "device", "address", "peer1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "prefix_count", "number of prefixes received per session", labelNames),
+					prometheus.GaugeValue, 42, "device", "address", "peer1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "uptime", "bgp session uptime in seconds", labelNames),
+					prometheus.GaugeValue, 3723, "device", "address", "peer1", "65000", "1.1.1.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "remote_hold_time", "hold time announced by the remote peer in seconds", labelNames),
+					prometheus.GaugeValue, 180, "device", "address", "peer1", "65000", "1.1.1.1",
 				),
 			},
 		},
 		{
 			name: "fetch error",
 			setMocks: func() {
-				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
-					r.Equal([]string{
-						"/routing/bgp/session/print",
-						"=.proplist=name,remote.as,remote.address,established,remote.messages,local.messages,remote.bytes,local.bytes",
-					}, sentence)
-				}).Return(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When(sessionSentence...).Then(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When(connectionSentence...).Then(&routeros.Reply{}, nil)
 			},
 			errWant: "failed to fetch bgp metrics: some fetch error",
 		},
+		{
+			name: "connections fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(sessionSentence...).Then(&routeros.Reply{}, nil)
+				routerOSClientMock.RunMock.When(connectionSentence...).Then(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch bgp connections: some fetch error",
+		},
 		{
 			name: "parse error",
 			setMocks: func() {
-				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
-					r.Equal([]string{
-						"/routing/bgp/session/print",
-						"=.proplist=name,remote.as,remote.address,established,remote.messages,local.messages,remote.bytes,local.bytes",
-					}, sentence)
-				}).Return(&routeros.Reply{
+				routerOSClientMock.RunMock.When(sessionSentence...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
 						{
 							Map: map[string]string{
@@ -144,14 +273,18 @@ func Test_bgpCollector_Collect(t *testing.T) {
 								"established":     "true",
 								"remote.messages": "d111",
 								"local.messages":  "11",
-								"remote.bytes":    "222",
-								"local.bytes":     "21",
+								"uptime":          "1x",
+								"last-started":    "yesterday",
 							},
 						},
 					},
 				}, nil)
+				routerOSClientMock.RunMock.When(connectionSentence...).Then(&routeros.Reply{}, nil)
 			},
 			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "session", "65000", "1.1.1.1", "", "", "", "", "",
+				),
 				prometheus.MustNewConstMetric(
 					metrics.BuildMetricDescription(prefix, "established", "bgp session established (up = 1)", labelNames),
 					prometheus.GaugeValue, 1, "device", "address", "session", "65000", "1.1.1.1",
@@ -160,14 +293,6 @@ func Test_bgpCollector_Collect(t *testing.T) {
 					metrics.BuildMetricDescription(prefix, "local_messages", "number of bgp messages sent per session", labelNames),
 					prometheus.CounterValue, 11, "device", "address", "session", "65000", "1.1.1.1",
 				),
-				prometheus.MustNewConstMetric(
-					metrics.BuildMetricDescription(prefix, "remote_bytes", "number of bytes received per session", labelNames),
-					prometheus.CounterValue, 222, "device", "address", "session", "65000", "1.1.1.1",
-				),
-				prometheus.MustNewConstMetric(
-					metrics.BuildMetricDescription(prefix, "local_bytes", "number of bytes sent per session", labelNames),
-					prometheus.CounterValue, 21, "device", "address", "session", "65000", "1.1.1.1",
-				),
 			},
 		},
 	}
@@ -193,6 +318,7 @@ func Test_bgpCollector_Collect(t *testing.T) {
 				MetricsChan:    ch,
 				DeviceName:     "device",
 				DeviceAddress:  "address",
+				Capabilities:   tc.capabilities,
 			})
 			close(ch)
 			if len(tc.errWant) != 0 {
@@ -206,3 +332,40 @@ func Test_bgpCollector_Collect(t *testing.T) {
 		})
 	}
 }
+
+func Test_findConnection(t *testing.T) {
+	r := require.New(t)
+
+	connections := map[string]*proto.Sentence{
+		"peer1":   {Map: map[string]string{"name": "peer1"}},
+		"xpeer1":  {Map: map[string]string{"name": "xpeer1"}},
+		"peer1-2": {Map: map[string]string{"name": "peer1-2"}},
+	}
+
+	testCases := []struct {
+		session string
+		want    string
+	}{
+		{session: "peer1", want: "peer1"},
+		{session: "peer1-1", want: "peer1"},
+		{session: "xpeer1-1", want: "xpeer1"},
+		{session: "peer1-2", want: "peer1-2"},
+		{session: "peer1-2-1", want: "peer1-2"},
+		{session: "peer1-backup"},
+		{session: "xpeer1-"},
+		{session: "peer2-1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.session, func(t *testing.T) {
+			got := findConnection(tc.session, connections)
+			if len(tc.want) == 0 {
+				r.Nil(got)
+				return
+			}
+
+			r.NotNil(got)
+			r.Equal(tc.want, got.Map["name"])
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package bonding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	properties        = []string{"name", "mode", "slaves"}
+	monitorProperties = []string{"active-ports", "active-slaves"}
+	slaveProperties   = []string{"interface", "partner-sys-id", "partner-key", "partner-port-number", "actor-state", "partner-state"}
+	lacpStateFlags    = []string{"active", "short-timeout", "aggregation", "synchronization", "collecting", "distributing", "defaulted", "expired"}
+	bondLabelNames    = []string{"name", "address", "bond", "mode"}
+	slaveLabelNames   = []string{"name", "address", "bond", "slave"}
+
+	slavesMetricDescription           = metrics.BuildMetricDescription(prefix, "slaves", "number of configured slaves on bonding interface", bondLabelNames)
+	activeSlavesMetricDescription     = metrics.BuildMetricDescription(prefix, "active_slaves", "number of active slaves on bonding interface", bondLabelNames)
+	slaveActiveMetricDescription      = metrics.BuildMetricDescription(prefix, "slave_active", "bonding slave status (active = 1)", slaveLabelNames)
+	slaveLACPStateMetricDescription   = metrics.BuildMetricDescription(prefix, "slave_lacp_state", "bonding slave lacp state flag (set = 1)", append(slaveLabelNames, "side", "flag"))
+	slavePartnerInfoMetricDescription = metrics.BuildMetricDescription(prefix, "slave_partner_info", "bonding slave lacp partner info",
+		append(slaveLabelNames, "partner_system_id", "partner_key", "partner_port"),
+	)
+)
+
+const (
+	prefix = "bonding"
+
+	modeLACP = "802.3ad"
+)
+
+type bondingCollector struct{}
+
+func NewCollector() *bondingCollector {
+	return &bondingCollector{}
+}
+
+func (c *bondingCollector) Name() string {
+	return prefix
+}
+
+func (c *bondingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- slavesMetricDescription
+	ch <- activeSlavesMetricDescription
+	ch <- slaveActiveMetricDescription
+	ch <- slaveLACPStateMetricDescription
+	ch <- slavePartnerInfoMetricDescription
+}
+
+func (c *bondingCollector) Collect(ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/bonding/print",
+		"?disabled=false",
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bonding interfaces: %w", err)
+	}
+
+	if len(reply.Re) == 0 {
+		return nil
+	}
+
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		return c.collectForBonds(reply.Re, ctx)
+	})
+
+	for _, re := range reply.Re {
+		if re.Map["mode"] != modeLACP {
+			continue
+		}
+
+		bond := re.Map["name"]
+		eg.Go(func() error {
+			return c.collectForSlaves(bond, ctx)
+		})
+	}
+
+	return eg.Wait()
+}
+
+func (c *bondingCollector) collectForBonds(bonds []*proto.Sentence, ctx *context.Context) error {
+	names := make([]string, 0, len(bonds))
+	for _, re := range bonds {
+		names = append(names, re.Map["name"])
+	}
+
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/bonding/monitor",
+		"=numbers="+strings.Join(names, ","),
+		"=once=",
+		"=.proplist="+strings.Join(monitorProperties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bonding monitor: %w", err)
+	}
+
+	for i, re := range reply.Re {
+		if i >= len(bonds) {
+			break
+		}
+
+		c.collectForBond(bonds[i], re, ctx)
+	}
+
+	return nil
+}
+
+func (c *bondingCollector) collectForBond(bond, monitor *proto.Sentence, ctx *context.Context) {
+	slaves := splitList(bond.Map["slaves"])
+
+	// RouterOS v7 renamed slaves to ports
+	activeValue := monitor.Map["active-ports"]
+	if len(activeValue) == 0 {
+		activeValue = monitor.Map["active-slaves"]
+	}
+
+	active := make(map[string]bool)
+	for _, s := range splitList(activeValue) {
+		active[s] = true
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(slavesMetricDescription, prometheus.GaugeValue, float64(len(slaves)),
+		ctx.DeviceName, ctx.DeviceAddress, bond.Map["name"], bond.Map["mode"],
+	)
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(activeSlavesMetricDescription, prometheus.GaugeValue, float64(len(active)),
+		ctx.DeviceName, ctx.DeviceAddress, bond.Map["name"], bond.Map["mode"],
+	)
+
+	for _, s := range slaves {
+		var v float64
+		if active[s] {
+			v = 1
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(slaveActiveMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, bond.Map["name"], s,
+		)
+	}
+}
+
+func (c *bondingCollector) collectForSlaves(bond string, ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/bonding/monitor-slaves",
+		"=bond="+bond,
+		"=once=",
+		"=.proplist="+strings.Join(slaveProperties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bonding slaves monitor: %w", err)
+	}
+
+	for _, re := range reply.Re {
+		c.collectForSlave(bond, re, ctx)
+	}
+
+	return nil
+}
+
+func (c *bondingCollector) collectForSlave(bond string, re *proto.Sentence, ctx *context.Context) {
+	slave := re.Map["interface"]
+
+	for _, side := range []string{"actor", "partner"} {
+		set := make(map[string]bool)
+		for _, f := range splitList(re.Map[side+"-state"]) {
+			set[f] = true
+		}
+
+		for _, f := range lacpStateFlags {
+			var v float64
+			if set[f] {
+				v = 1
+			}
+
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(slaveLACPStateMetricDescription, prometheus.GaugeValue, v,
+				ctx.DeviceName, ctx.DeviceAddress, bond, slave, side, f,
+			)
+		}
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(slavePartnerInfoMetricDescription, prometheus.GaugeValue, 1,
+		ctx.DeviceName, ctx.DeviceAddress, bond, slave,
+		re.Map["partner-sys-id"], re.Map["partner-key"], re.Map["partner-port-number"],
+	)
+}
+
+func splitList(value string) []string {
+	if len(value) == 0 {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,235 @@
+package bonding
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_bondingCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("bonding", c.Name())
+}
+
+func Test_bondingCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "slaves", "number of configured slaves on bonding interface", bondLabelNames),
+		metrics.BuildMetricDescription(prefix, "active_slaves", "number of active slaves on bonding interface", bondLabelNames),
+		metrics.BuildMetricDescription(prefix, "slave_active", "bonding slave status (active = 1)", slaveLabelNames),
+		metrics.BuildMetricDescription(prefix, "slave_lacp_state", "bonding slave lacp state flag (set = 1)", append(slaveLabelNames, "side", "flag")),
+		metrics.BuildMetricDescription(prefix, "slave_partner_info", "bonding slave lacp partner info",
+			append(slaveLabelNames, "partner_system_id", "partner_key", "partner_port"),
+		),
+	}, got)
+}
+
+func Test_bondingCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	lacpStateDesc := metrics.BuildMetricDescription(prefix, "slave_lacp_state", "bonding slave lacp state flag (set = 1)", append(slaveLabelNames, "side", "flag"))
+	actorState := map[string]float64{"active": 1, "aggregation": 1, "synchronization": 1, "collecting": 1, "distributing": 1}
+	partnerState := map[string]float64{"active": 1, "aggregation": 1, "defaulted": 1}
+	var lacpStateMetrics []prometheus.Metric
+	for _, f := range lacpStateFlags {
+		lacpStateMetrics = append(lacpStateMetrics,
+			prometheus.MustNewConstMetric(lacpStateDesc, prometheus.GaugeValue, actorState[f], "device", "address", "bond1", "ether1", "actor", f),
+			prometheus.MustNewConstMetric(lacpStateDesc, prometheus.GaugeValue, partnerState[f], "device", "address", "bond1", "ether1", "partner", f),
+		)
+	}
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bonding/print",
+					"?disabled=false",
+					"=.proplist=name,mode,slaves",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":   "bond1",
+								"mode":   "802.3ad",
+								"slaves": "ether1,ether2",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":   "bond2",
+								"mode":   "active-backup",
+								"slaves": "ether3,ether4",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bonding/monitor",
+					"=numbers=bond1,bond2",
+					"=once=",
+					"=.proplist=active-ports,active-slaves",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"active-ports": "ether1",
+							},
+						},
+						{
+							Map: map[string]string{
+								"active-slaves": "ether3",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bonding/monitor-slaves",
+					"=bond=bond1",
+					"=once=",
+					"=.proplist=interface,partner-sys-id,partner-key,partner-port-number,actor-state,partner-state",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"interface":           "ether1",
+								"partner-sys-id":      "00:00:00:00:00:01",
+								"partner-key":         "15",
+								"partner-port-number": "1",
+								"actor-state":         "active,aggregation,synchronization,collecting,distributing",
+								"partner-state":       "active,aggregation,defaulted",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: append([]prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "slaves", "number of configured slaves on bonding interface", bondLabelNames),
+					prometheus.GaugeValue, 2, "device", "address", "bond1", "802.3ad",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "active_slaves", "number of active slaves on bonding interface", bondLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "bond1", "802.3ad",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "slave_active", "bonding slave status (active = 1)", slaveLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "bond1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "slave_active", "bonding slave status (active = 1)", slaveLabelNames),
+					prometheus.GaugeValue, 0, "device", "address", "bond1", "ether2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "slaves", "number of configured slaves on bonding interface", bondLabelNames),
+					prometheus.GaugeValue, 2, "device", "address", "bond2", "active-backup",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "active_slaves", "number of active slaves on bonding interface", bondLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "bond2", "active-backup",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "slave_active", "bonding slave status (active = 1)", slaveLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "bond2", "ether3",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "slave_active", "bonding slave status (active = 1)", slaveLabelNames),
+					prometheus.GaugeValue, 0, "device", "address", "bond2", "ether4",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "slave_partner_info", "bonding slave lacp partner info",
+						append(slaveLabelNames, "partner_system_id", "partner_key", "partner_port"),
+					),
+					prometheus.GaugeValue, 1, "device", "address", "bond1", "ether1", "00:00:00:00:00:01", "15", "1",
+				),
+			}, lacpStateMetrics...),
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
+					r.Equal([]string{
+						"/interface/bonding/print",
+						"?disabled=false",
+						"=.proplist=name,mode,slaves",
+					}, sentence)
+				}).Return(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch bonding interfaces: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
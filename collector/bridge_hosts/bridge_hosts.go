@@ -16,14 +16,34 @@ var (
 	metricDescription = metrics.BuildMetricDescription(prefix, "status", "bridge host status",
 		[]string{"name", "address", "bridge", "mac_address", "on_interface", "dynamic", "local", "external"},
 	)
+	countMetricDescription = metrics.BuildMetricDescription(prefix, "count", "number of bridge hosts",
+		[]string{"name", "address", "bridge", "on_interface"},
+	)
 )
 
 const prefix = "bridge_host"
 
-type bridgeHostsCollector struct{}
+type bridgeHostsCollector struct {
+	seriesLimit int
+}
+
+// Option - represents a function on bridge hosts collector instance
+type Option func(*bridgeHostsCollector)
+
+// WithSeriesLimit - exports only host counts when a device has more bridge hosts than limit
+func WithSeriesLimit(limit int) Option {
+	return func(c *bridgeHostsCollector) {
+		c.seriesLimit = limit
+	}
+}
+
+func NewCollector(opts ...Option) *bridgeHostsCollector {
+	c := &bridgeHostsCollector{}
+	for _, o := range opts {
+		o(c)
+	}
 
-func NewCollector() *bridgeHostsCollector {
-	return &bridgeHostsCollector{}
+	return c
 }
 
 func (c *bridgeHostsCollector) Name() string {
@@ -32,6 +52,10 @@ func (c *bridgeHostsCollector) Name() string {
 
 func (c *bridgeHostsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- metricDescription
+	ch <- countMetricDescription
+	if c.seriesLimit > 0 {
+		ch <- metrics.SeriesTruncatedMetricDescription
+	}
 }
 
 func (c *bridgeHostsCollector) Collect(ctx *context.Context) error {
@@ -40,6 +64,12 @@ func (c *bridgeHostsCollector) Collect(ctx *context.Context) error {
 		return fmt.Errorf("failed to fetch bridge hosts metrics: %w", err)
 	}
 
+	c.collectCounts(stats, ctx)
+
+	if metrics.CheckSeriesLimit(ctx, c.Name(), c.seriesLimit, len(stats)) {
+		return nil
+	}
+
 	for _, re := range stats {
 		c.collectForStat(re, ctx)
 	}
@@ -60,6 +90,24 @@ func (c *bridgeHostsCollector) fetch(ctx *context.Context) ([]*proto.Sentence, e
 	return reply.Re, nil
 }
 
+func (c *bridgeHostsCollector) collectCounts(stats []*proto.Sentence, ctx *context.Context) {
+	type countKey struct {
+		bridge      string
+		onInterface string
+	}
+
+	counts := make(map[countKey]float64)
+	for _, re := range stats {
+		counts[countKey{bridge: re.Map["bridge"], onInterface: re.Map["on-interface"]}]++
+	}
+
+	for k, v := range counts {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(countMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, k.bridge, k.onInterface,
+		)
+	}
+}
+
 func (c *bridgeHostsCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
 	ctx.MetricsChan <- prometheus.MustNewConstMetric(metricDescription, prometheus.GaugeValue, 1.0,
 		ctx.DeviceName, ctx.DeviceAddress,
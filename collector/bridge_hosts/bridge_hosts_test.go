@@ -45,13 +45,18 @@ func Test_bridgeHostsCollector_Describe(t *testing.T) {
 		metrics.BuildMetricDescription(prefix, "status", "bridge host status",
 			[]string{"name", "address", "bridge", "mac_address", "on_interface", "dynamic", "local", "external"},
 		),
+		metrics.BuildMetricDescription(prefix, "count", "number of bridge hosts",
+			[]string{"name", "address", "bridge", "on_interface"},
+		),
 	}, got)
 }
 
 func Test_bridgeHostsCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
-	c := NewCollector()
+	countDesc := metrics.BuildMetricDescription(prefix, "count", "number of bridge hosts",
+		[]string{"name", "address", "bridge", "on_interface"},
+	)
 
 	routerOSClientMock := mocks.NewClientMock(t)
 	resetMocks := func() {
@@ -60,6 +65,7 @@ func Test_bridgeHostsCollector_Collect(t *testing.T) {
 
 	testCases := []struct {
 		name     string
+		opts     []Option
 		setMocks func()
 		want     []prometheus.Metric
 		errWant  string
@@ -95,6 +101,33 @@ func Test_bridgeHostsCollector_Collect(t *testing.T) {
 					),
 					prometheus.GaugeValue, 1.0, "device", "address", "bridge", "mac-address", "ether1", "true", "false", "true",
 				),
+				prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 1, "device", "address", "bridge", "ether1"),
+			},
+		},
+		{
+			name: "series limit exceeded",
+			opts: []Option{WithSeriesLimit(1)},
+			setMocks: func() {
+				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
+					r.Equal([]string{
+						"/interface/bridge/host/print",
+						"?disabled=false",
+						"=.proplist=bridge,mac-address,on-interface,dynamic,local,external",
+					}, sentence)
+				}).Return(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"bridge": "bridge", "mac-address": "mac-address-1", "on-interface": "ether1"}},
+						{Map: map[string]string{"bridge": "bridge", "mac-address": "mac-address-2", "on-interface": "ether1"}},
+						{Map: map[string]string{"bridge": "bridge", "mac-address": "mac-address-3", "on-interface": "ether2"}},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 2, "device", "address", "bridge", "ether1"),
+				prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 1, "device", "address", "bridge", "ether2"),
+				prometheus.MustNewConstMetric(metrics.SeriesTruncatedMetricDescription, prometheus.GaugeValue, 1,
+					"device", "address", "bridge_host",
+				),
 			},
 		},
 		{
@@ -114,6 +147,7 @@ func Test_bridgeHostsCollector_Collect(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			c := NewCollector(tc.opts...)
 			resetMocks()
 			tc.setMocks()
 			defer routerOSClientMock.MinimockFinish()
@@ -0,0 +1,214 @@
+package bridge_ports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	monitorProperties = []string{"root-bridge", "root-bridge-id", "root-port", "root-path-cost", "port-count", "designated-port-count", "topology-change-count"}
+	portProperties    = []string{"interface", "bridge", "role", "forwarding", "learning"}
+	portRoles         = []string{"root", "designated", "alternate", "backup", "disabled"}
+	bridgeLabelNames  = []string{"name", "address", "bridge"}
+	portLabelNames    = []string{"name", "address", "bridge", "interface"}
+
+	bridgeMetricDescriptions = map[string]*metrics.MetricDescription{
+		"root-path-cost": {
+			Desc:      metrics.BuildMetricDescription(prefix, "root_path_cost", "bridge path cost to the root bridge", bridgeLabelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"port-count": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_count", "number of bridge ports", bridgeLabelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"designated-port-count": {
+			Desc:      metrics.BuildMetricDescription(prefix, "designated_port_count", "number of designated bridge ports", bridgeLabelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"topology-change-count": {
+			Desc:      metrics.BuildMetricDescription(prefix, "topology_changes", "number of spanning tree topology changes", bridgeLabelNames),
+			ValueType: prometheus.CounterValue,
+		},
+	}
+	rootBridgeMetricDescription = metrics.BuildMetricDescription(prefix, "root_bridge", "bridge is the spanning tree root bridge (root = 1)",
+		append(bridgeLabelNames, "root_bridge_id", "root_port"),
+	)
+	portRoleMetricDescription   = metrics.BuildMetricDescription(prefix, "port_role", "bridge port spanning tree role (current role = 1)", append(portLabelNames, "role"))
+	portStateMetricDescriptions = map[string]*prometheus.Desc{
+		"forwarding": metrics.BuildMetricDescription(prefix, "port_forwarding", "bridge port forwarding state (forwarding = 1, discarding = 0)", portLabelNames),
+		"learning":   metrics.BuildMetricDescription(prefix, "port_learning", "bridge port learning state (learning = 1)", portLabelNames),
+	}
+)
+
+const prefix = "bridge"
+
+type bridgePortsCollector struct{}
+
+func NewCollector() *bridgePortsCollector {
+	return &bridgePortsCollector{}
+}
+
+func (c *bridgePortsCollector) Name() string {
+	return prefix
+}
+
+func (c *bridgePortsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range bridgeMetricDescriptions {
+		ch <- d.Desc
+	}
+	ch <- rootBridgeMetricDescription
+	ch <- portRoleMetricDescription
+	for _, d := range portStateMetricDescriptions {
+		ch <- d
+	}
+}
+
+func (c *bridgePortsCollector) Collect(ctx *context.Context) error {
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		return c.collectBridges(ctx)
+	})
+
+	eg.Go(func() error {
+		return c.collectPorts(ctx)
+	})
+
+	return eg.Wait()
+}
+
+func (c *bridgePortsCollector) collectBridges(ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/bridge/print",
+		"?disabled=false",
+		"=.proplist=name",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bridge names: %w", err)
+	}
+
+	if len(reply.Re) == 0 {
+		return nil
+	}
+
+	bridges := make([]string, 0, len(reply.Re))
+	for _, re := range reply.Re {
+		bridges = append(bridges, re.Map["name"])
+	}
+
+	reply, err = ctx.RouterOSClient.Run(
+		"/interface/bridge/monitor",
+		"=numbers="+strings.Join(bridges, ","),
+		"=once=",
+		"=.proplist="+strings.Join(monitorProperties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bridge monitor: %w", err)
+	}
+
+	for i, re := range reply.Re {
+		if i >= len(bridges) {
+			break
+		}
+
+		c.collectForBridge(bridges[i], re, ctx)
+	}
+
+	return nil
+}
+
+func (c *bridgePortsCollector) collectForBridge(bridge string, re *proto.Sentence, ctx *context.Context) {
+	for p := range bridgeMetricDescriptions {
+		value := re.Map[p]
+		if len(value) == 0 {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"bridge":    bridge,
+				"property":  p,
+				"value":     value,
+				"error":     err,
+			}).Error("failed to parse bridge metric value")
+			continue
+		}
+
+		desc := bridgeMetricDescriptions[p]
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, v,
+			ctx.DeviceName, ctx.DeviceAddress, bridge,
+		)
+	}
+
+	value := re.Map["root-bridge"]
+	if len(value) == 0 {
+		return
+	}
+
+	var v float64
+	if value == "true" {
+		v = 1
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(rootBridgeMetricDescription, prometheus.GaugeValue, v,
+		ctx.DeviceName, ctx.DeviceAddress, bridge, re.Map["root-bridge-id"], re.Map["root-port"],
+	)
+}
+
+func (c *bridgePortsCollector) collectPorts(ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/bridge/port/print",
+		"?disabled=false",
+		"=.proplist="+strings.Join(portProperties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bridge ports: %w", err)
+	}
+
+	for _, re := range reply.Re {
+		c.collectForPort(re, ctx)
+	}
+
+	return nil
+}
+
+func (c *bridgePortsCollector) collectForPort(re *proto.Sentence, ctx *context.Context) {
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, re.Map["bridge"], re.Map["interface"]}
+
+	if value := re.Map["role"]; len(value) != 0 {
+		role := strings.TrimSuffix(value, "-port")
+		for _, r := range portRoles {
+			var v float64
+			if r == role {
+				v = 1
+			}
+
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(portRoleMetricDescription, prometheus.GaugeValue, v, append(labelValues, r)...)
+		}
+	}
+
+	for p, desc := range portStateMetricDescriptions {
+		value := re.Map[p]
+		if len(value) == 0 {
+			continue
+		}
+
+		var v float64
+		if value == "true" {
+			v = 1
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labelValues...)
+	}
+}
@@ -0,0 +1,215 @@
+package bridge_ports
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_bridgePortsCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("bridge", c.Name())
+}
+
+func Test_bridgePortsCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "root_path_cost", "bridge path cost to the root bridge", bridgeLabelNames),
+		metrics.BuildMetricDescription(prefix, "port_count", "number of bridge ports", bridgeLabelNames),
+		metrics.BuildMetricDescription(prefix, "designated_port_count", "number of designated bridge ports", bridgeLabelNames),
+		metrics.BuildMetricDescription(prefix, "topology_changes", "number of spanning tree topology changes", bridgeLabelNames),
+		metrics.BuildMetricDescription(prefix, "root_bridge", "bridge is the spanning tree root bridge (root = 1)",
+			append(bridgeLabelNames, "root_bridge_id", "root_port"),
+		),
+		metrics.BuildMetricDescription(prefix, "port_role", "bridge port spanning tree role (current role = 1)", append(portLabelNames, "role")),
+		metrics.BuildMetricDescription(prefix, "port_forwarding", "bridge port forwarding state (forwarding = 1, discarding = 0)", portLabelNames),
+		metrics.BuildMetricDescription(prefix, "port_learning", "bridge port learning state (learning = 1)", portLabelNames),
+	}, got)
+}
+
+func Test_bridgePortsCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	portRoleDesc := metrics.BuildMetricDescription(prefix, "port_role", "bridge port spanning tree role (current role = 1)", append(portLabelNames, "role"))
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bridge/print",
+					"?disabled=false",
+					"=.proplist=name",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name": "bridge1",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bridge/monitor",
+					"=numbers=bridge1",
+					"=once=",
+					"=.proplist=root-bridge,root-bridge-id,root-port,root-path-cost,port-count,designated-port-count,topology-change-count",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"root-bridge":           "false",
+								"root-bridge-id":        "0x8000.00:00:00:00:00:01",
+								"root-port":             "ether1",
+								"root-path-cost":        "10",
+								"port-count":            "2",
+								"designated-port-count": "1",
+								"topology-change-count": "a5",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bridge/port/print",
+					"?disabled=false",
+					"=.proplist=interface,bridge,role,forwarding,learning",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"interface":  "ether1",
+								"bridge":     "bridge1",
+								"role":       "root-port",
+								"forwarding": "true",
+								"learning":   "true",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "root_path_cost", "bridge path cost to the root bridge", bridgeLabelNames),
+					prometheus.GaugeValue, 10, "device", "address", "bridge1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "port_count", "number of bridge ports", bridgeLabelNames),
+					prometheus.GaugeValue, 2, "device", "address", "bridge1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "designated_port_count", "number of designated bridge ports", bridgeLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "bridge1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "root_bridge", "bridge is the spanning tree root bridge (root = 1)",
+						append(bridgeLabelNames, "root_bridge_id", "root_port"),
+					),
+					prometheus.GaugeValue, 0, "device", "address", "bridge1", "0x8000.00:00:00:00:00:01", "ether1",
+				),
+				prometheus.MustNewConstMetric(portRoleDesc, prometheus.GaugeValue, 1, "device", "address", "bridge1", "ether1", "root"),
+				prometheus.MustNewConstMetric(portRoleDesc, prometheus.GaugeValue, 0, "device", "address", "bridge1", "ether1", "designated"),
+				prometheus.MustNewConstMetric(portRoleDesc, prometheus.GaugeValue, 0, "device", "address", "bridge1", "ether1", "alternate"),
+				prometheus.MustNewConstMetric(portRoleDesc, prometheus.GaugeValue, 0, "device", "address", "bridge1", "ether1", "backup"),
+				prometheus.MustNewConstMetric(portRoleDesc, prometheus.GaugeValue, 0, "device", "address", "bridge1", "ether1", "disabled"),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "port_forwarding", "bridge port forwarding state (forwarding = 1, discarding = 0)", portLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "bridge1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "port_learning", "bridge port learning state (learning = 1)", portLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "bridge1", "ether1",
+				),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bridge/print",
+					"?disabled=false",
+					"=.proplist=name",
+				}...).Then(&routeros.Reply{}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/bridge/port/print",
+					"?disabled=false",
+					"=.proplist=interface,bridge,role,forwarding,learning",
+				}...).Then(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch bridge ports: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
@@ -48,14 +48,32 @@ var (
 			ValueType: prometheus.CounterValue,
 		},
 	}
+	countMetricDescription = metrics.BuildMetricDescription(prefix, "count", "number of capsman clients", []string{"name", "address", "interface", "ssid"})
 )
 
 const prefix = "capsman_client"
 
-type capsmanCollector struct{}
+type capsmanCollector struct {
+	seriesLimit int
+}
+
+// Option - represents a function on capsman collector instance
+type Option func(*capsmanCollector)
 
-func NewCollector() *capsmanCollector {
-	return &capsmanCollector{}
+// WithSeriesLimit - exports only client counts when a device has more clients than limit
+func WithSeriesLimit(limit int) Option {
+	return func(c *capsmanCollector) {
+		c.seriesLimit = limit
+	}
+}
+
+func NewCollector(opts ...Option) *capsmanCollector {
+	c := &capsmanCollector{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
 }
 
 func (c *capsmanCollector) Name() string {
@@ -66,6 +84,16 @@ func (c *capsmanCollector) Describe(ch chan<- *prometheus.Desc) {
 	for _, d := range metricDescriptions {
 		ch <- d.Desc
 	}
+
+	ch <- countMetricDescription
+	if c.seriesLimit > 0 {
+		ch <- metrics.SeriesTruncatedMetricDescription
+	}
+}
+
+// Supported - the legacy wireless menus are only available with the wireless package installed
+func (c *capsmanCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayHavePackage("wireless")
 }
 
 func (c *capsmanCollector) Collect(ctx *context.Context) error {
@@ -74,6 +102,12 @@ func (c *capsmanCollector) Collect(ctx *context.Context) error {
 		return fmt.Errorf("failed to fetch capsman station metrics: %w", err)
 	}
 
+	c.collectCounts(stats, ctx)
+
+	if metrics.CheckSeriesLimit(ctx, c.Name(), c.seriesLimit, len(stats)) {
+		return nil
+	}
+
 	for _, re := range stats {
 		c.collectForStat(re, ctx)
 	}
@@ -93,6 +127,24 @@ func (c *capsmanCollector) fetch(ctx *context.Context) ([]*proto.Sentence, error
 	return reply.Re, nil
 }
 
+func (c *capsmanCollector) collectCounts(stats []*proto.Sentence, ctx *context.Context) {
+	type countKey struct {
+		iface string
+		ssid  string
+	}
+
+	counts := make(map[countKey]float64)
+	for _, re := range stats {
+		counts[countKey{iface: re.Map["interface"], ssid: re.Map["ssid"]}]++
+	}
+
+	for k, v := range counts {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(countMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, k.iface, k.ssid,
+		)
+	}
+}
+
 func (c *capsmanCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
 	for _, p := range metricProperties {
 		c.collectMetricForProperty(p, re, ctx)
@@ -49,14 +49,25 @@ func Test_capsmanCollector_Describe(t *testing.T) {
 		metrics.BuildMetricDescription(prefix, "tx_bytes", "capsman client tx bytes count", labelNames),
 		metrics.BuildMetricDescription(prefix, "rx_packets", "capsman client rx packets count", labelNames),
 		metrics.BuildMetricDescription(prefix, "rx_bytes", "capsman client rx bytes count", labelNames),
+		metrics.BuildMetricDescription(prefix, "count", "number of capsman clients", []string{"name", "address", "interface", "ssid"}),
 	}, got)
 }
 
-func Test_capsmanCollector_Collect(t *testing.T) {
+func Test_capsmanCollector_Supported(t *testing.T) {
 	r := require.New(t)
 
 	c := NewCollector()
 
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Packages: []string{"routeros", "wireless"}}))
+	r.False(c.Supported(&context.Capabilities{Packages: []string{"routeros", "wifi-qcom"}}))
+}
+
+func Test_capsmanCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	countDesc := metrics.BuildMetricDescription(prefix, "count", "number of capsman clients", []string{"name", "address", "interface", "ssid"})
+
 	routerOSClientMock := mocks.NewClientMock(t)
 	resetMocks := func() {
 		routerOSClientMock = mocks.NewClientMock(t)
@@ -64,6 +75,7 @@ func Test_capsmanCollector_Collect(t *testing.T) {
 
 	testCases := []struct {
 		name     string
+		opts     []Option
 		setMocks func()
 		want     []prometheus.Metric
 		errWant  string
@@ -122,6 +134,30 @@ func Test_capsmanCollector_Collect(t *testing.T) {
 					metrics.BuildMetricDescription(prefix, "rx_bytes", "capsman client rx bytes count", labelNames),
 					prometheus.CounterValue, 200, "device", "address", "wlan1", "mac-address", "ssid",
 				),
+				prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 1, "device", "address", "wlan1", "ssid"),
+			},
+		},
+		{
+			name: "series limit exceeded",
+			opts: []Option{WithSeriesLimit(1)},
+			setMocks: func() {
+				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
+					r.Equal([]string{
+						"/caps-man/registration-table/print",
+						"=.proplist=interface,mac-address,ssid,uptime,tx-signal,rx-signal,packets,bytes",
+					}, sentence)
+				}).Return(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"interface": "wlan1", "mac-address": "mac-address-1", "ssid": "ssid", "tx-signal": "20"}},
+						{Map: map[string]string{"interface": "wlan1", "mac-address": "mac-address-2", "ssid": "ssid", "tx-signal": "25"}},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 2, "device", "address", "wlan1", "ssid"),
+				prometheus.MustNewConstMetric(metrics.SeriesTruncatedMetricDescription, prometheus.GaugeValue, 1,
+					"device", "address", "capsman_client",
+				),
 			},
 		},
 		{
@@ -182,12 +218,14 @@ func Test_capsmanCollector_Collect(t *testing.T) {
 					metrics.BuildMetricDescription(prefix, "rx_packets", "capsman client rx packets count", labelNames),
 					prometheus.CounterValue, 10, "device", "address", "wlan1", "mac-address", "ssid",
 				),
+				prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 1, "device", "address", "wlan1", "ssid"),
 			},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			c := NewCollector(tc.opts...)
 			resetMocks()
 			tc.setMocks()
 			defer routerOSClientMock.MinimockFinish()
@@ -10,6 +10,7 @@ import (
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
 	"github.com/psolru/mikrotik-exporter/dns"
+	"github.com/psolru/mikrotik-exporter/relabel"
 	"github.com/psolru/mikrotik-exporter/routeros"
 )
 
@@ -26,6 +27,12 @@ var (
 		[]string{"device", "collector", "success"},
 		nil,
 	)
+	collectorUnsupportedMetricDescription = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "collector", "unsupported"),
+		"whether the collector was skipped because the device doesn't support it (unsupported = 1)",
+		[]string{"device", "collector"},
+		nil,
+	)
 
 	timeNowUTC = func() time.Time {
 		return time.Now().UTC()
@@ -44,6 +51,12 @@ type (
 		Collect(ctx *context.Context) error
 	}
 
+	// SupportChecker - optionally implemented by feature collectors, which depend on device capabilities,
+	// unsupported collectors are skipped and reported by the collector unsupported metric
+	SupportChecker interface {
+		Supported(caps *context.Capabilities) bool
+	}
+
 	// Device - represents device configuration for collector
 	Device struct {
 		// Name - device name
@@ -62,6 +75,8 @@ type (
 		DNSRecord *Record
 		// Collectors - list of enabled collectors for device
 		Collectors []FeatureCollector
+		// RelabelRules - list of relabel rules applied to device metrics after the app level ones, optional
+		RelabelRules []*relabel.Rule
 	}
 
 	// Client - represents routerOS client configuration
@@ -92,6 +107,16 @@ type (
 		dnsLookupFunc     dnsLookupFunc
 		devices           []*Device
 		collectors        []FeatureCollector
+		relabelRules      []*relabel.Rule
+
+		capabilitiesMu sync.Mutex
+		capabilities   map[*Device]cachedCapabilities
+	}
+
+	// cachedCapabilities - represents probed device capabilities, which are reused until they expire
+	cachedCapabilities struct {
+		caps      *context.Capabilities
+		expiresAt time.Time
 	}
 )
 
@@ -104,18 +129,25 @@ const (
 
 	resultError   = "false"
 	resultSuccess = "true"
+
+	// capabilitiesTTL - how long probed capabilities are reused, so that upgrades and package changes are picked up
+	capabilitiesTTL = 10 * time.Minute
 )
 
 func buildCollectorContext(
 	ch chan<- prometheus.Metric,
 	device *Device,
 	runner routeros.Client,
+	newRunner func() (routeros.Client, error),
+	caps *context.Capabilities,
 ) *context.Context {
 	return &context.Context{
-		MetricsChan:    ch,
-		RouterOSClient: runner,
-		DeviceName:     device.Name,
-		DeviceAddress:  device.Address,
+		MetricsChan:       ch,
+		RouterOSClient:    runner,
+		NewRouterOSClient: newRunner,
+		DeviceName:        device.Name,
+		DeviceAddress:     device.Address,
+		Capabilities:      caps,
 	}
 }
 
@@ -140,6 +172,13 @@ func WithCollectors(fc ...FeatureCollector) Option {
 	}
 }
 
+// WithRelabelRules - adds relabel rules applied to the metrics of all devices
+func WithRelabelRules(rules ...*relabel.Rule) Option {
+	return func(c *routerosCollector) {
+		c.relabelRules = append(c.relabelRules, rules...)
+	}
+}
+
 // Option - represents a function on routeros collector instance
 type Option func(*routerosCollector)
 
@@ -154,6 +193,7 @@ func NewMikrotikCollector(devices []*Device, opts ...Option) prometheus.Collecto
 		dnsLookupFunc:     dns.LookupAddressFromSRVRecord,
 		devices:           devices,
 		collectors:        make([]FeatureCollector, 0),
+		capabilities:      make(map[*Device]cachedCapabilities),
 	}
 
 	for _, o := range opts {
@@ -167,6 +207,7 @@ func NewMikrotikCollector(devices []*Device, opts ...Option) prometheus.Collecto
 func (c *routerosCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- scrapeDurationMetricDescription
 	ch <- collectorDurationMetricDescription
+	ch <- collectorUnsupportedMetricDescription
 
 	for _, co := range c.collectors {
 		co.Describe(ch)
@@ -236,16 +277,39 @@ func (c *routerosCollector) connectAndCollect(d *Device, ch chan<- prometheus.Me
 
 	cl.Async()
 
-	// Merge app level collectors and device level collectors
-	collectors := append(c.collectors, d.Collectors...) // nolint:gocritic
+	collectors := mergeCollectors(c.collectors, d.Collectors)
+
+	caps := c.deviceCapabilities(d, cl)
 
-	ctx := buildCollectorContext(ch, d, cl)
+	metricsChan, waitRelabel := c.relabelMetrics(d, ch)
+	newClient := func() (routeros.Client, error) {
+		return c.clientCreatorFunc(d)
+	}
+	ctx := buildCollectorContext(metricsChan, d, cl, newClient, caps)
 	var wg sync.WaitGroup
 	wg.Add(len(collectors))
 	for _, co := range collectors {
 		go func(co FeatureCollector) {
 			defer wg.Done()
 
+			if sc, ok := co.(SupportChecker); ok {
+				var unsupported float64
+				if !sc.Supported(caps) {
+					unsupported = 1
+				}
+
+				ch <- prometheus.MustNewConstMetric(
+					collectorUnsupportedMetricDescription,
+					prometheus.GaugeValue,
+					unsupported,
+					d.Name, co.Name(),
+				)
+
+				if unsupported == 1 {
+					return
+				}
+			}
+
 			start := timeNowUTC()
 
 			if err = co.Collect(ctx); err != nil {
@@ -273,6 +337,7 @@ func (c *routerosCollector) connectAndCollect(d *Device, ch chan<- prometheus.Me
 	}
 
 	wg.Wait()
+	waitRelabel()
 
 	ch <- prometheus.MustNewConstMetric(
 		scrapeDurationMetricDescription,
@@ -283,3 +348,145 @@ func (c *routerosCollector) connectAndCollect(d *Device, ch chan<- prometheus.Me
 
 	return nil
 }
+
+// deviceCapabilities - returns the cached capabilities of the device or probes them, capabilities probed with
+// failures aren't cached, so that probing is retried on the next scrape
+func (c *routerosCollector) deviceCapabilities(d *Device, cl routeros.Client) *context.Capabilities {
+	now := timeNowUTC()
+
+	c.capabilitiesMu.Lock()
+	cached, found := c.capabilities[d]
+	c.capabilitiesMu.Unlock()
+	if found && now.Before(cached.expiresAt) {
+		return cached.caps
+	}
+
+	caps, complete := probeCapabilities(d, cl)
+	if complete {
+		c.capabilitiesMu.Lock()
+		c.capabilities[d] = cachedCapabilities{caps: caps, expiresAt: now.Add(capabilitiesTTL)}
+		c.capabilitiesMu.Unlock()
+	}
+
+	return caps
+}
+
+// probeCapabilities - fetches the device version, board, enabled packages and time zone, failures leave the
+// corresponding fields empty so that collectors fall back to their defaults and are reported by complete
+func probeCapabilities(d *Device, cl routeros.Client) (caps *context.Capabilities, complete bool) {
+	caps = &context.Capabilities{}
+	complete = true
+
+	reply, err := cl.Run("/system/resource/print", "=.proplist=version,architecture-name,board-name")
+	if err != nil {
+		log.WithFields(log.Fields{
+			"device": d.Name,
+			"error":  err,
+		}).Warn("failed to probe device resource")
+		complete = false
+	} else if len(reply.Re) != 0 {
+		caps.Version = reply.Re[0].Map["version"]
+		caps.Architecture = reply.Re[0].Map["architecture-name"]
+		caps.Board = reply.Re[0].Map["board-name"]
+	}
+
+	reply, err = cl.Run("/system/package/print", "?disabled=false", "=.proplist=name")
+	if err != nil {
+		log.WithFields(log.Fields{
+			"device": d.Name,
+			"error":  err,
+		}).Warn("failed to probe device packages")
+		complete = false
+	} else {
+		for _, re := range reply.Re {
+			caps.Packages = append(caps.Packages, re.Map["name"])
+		}
+	}
+
+	reply, err = cl.Run("/system/clock/print", "=.proplist=time-zone-name")
+	if err != nil {
+		log.WithFields(log.Fields{
+			"device": d.Name,
+			"error":  err,
+		}).Warn("failed to probe device time zone")
+		complete = false
+	} else if len(reply.Re) != 0 {
+		caps.TimeZone = loadLocation(d, reply.Re[0].Map["time-zone-name"])
+	}
+
+	return caps, complete
+}
+
+// loadLocation - returns the location of a RouterOS time zone name or nil if it is unknown
+func loadLocation(d *Device, name string) *time.Location {
+	if len(name) == 0 || name == "manual" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"device":    d.Name,
+			"time_zone": name,
+			"error":     err,
+		}).Warn("failed to load device time zone")
+		return nil
+	}
+
+	return loc
+}
+
+// mergeCollectors - merges app level and device level collectors, a device level collector replaces
+// the app level collector of the same name, e.g. an interface collector with a device level filter
+func mergeCollectors(appCollectors, deviceCollectors []FeatureCollector) []FeatureCollector {
+	deviceNames := make(map[string]bool, len(deviceCollectors))
+	for _, co := range deviceCollectors {
+		deviceNames[co.Name()] = true
+	}
+
+	collectors := make([]FeatureCollector, 0, len(appCollectors)+len(deviceCollectors))
+	for _, co := range appCollectors {
+		if !deviceNames[co.Name()] {
+			collectors = append(collectors, co)
+		}
+	}
+
+	return append(collectors, deviceCollectors...)
+}
+
+// relabelMetrics - returns the channel feature collectors should send metrics to and a func,
+// which waits until all of them are relabeled and forwarded to ch
+func (c *routerosCollector) relabelMetrics(d *Device, ch chan<- prometheus.Metric) (chan<- prometheus.Metric, func()) {
+	rules := make([]*relabel.Rule, 0, len(c.relabelRules)+len(d.RelabelRules))
+	rules = append(rules, c.relabelRules...)
+	rules = append(rules, d.RelabelRules...)
+	if len(rules) == 0 {
+		return ch, func() {}
+	}
+
+	in := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m := range in {
+			res, keep, err := relabel.Apply(m, rules)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"device": d.Name,
+					"metric": m.Desc().String(),
+					"error":  err,
+				}).Error("failed to relabel metric")
+				continue
+			}
+
+			if keep {
+				ch <- res
+			}
+		}
+	}()
+
+	return in, func() {
+		close(in)
+		<-done
+	}
+}
@@ -8,9 +8,13 @@ import (
 	"github.com/gojuno/minimock/v3"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
+	routerosAPI "gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
 	"github.com/psolru/mikrotik-exporter/collector/mocks"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/relabel"
 	"github.com/psolru/mikrotik-exporter/routeros"
 	routerosMocks "github.com/psolru/mikrotik-exporter/routeros/mocks"
 )
@@ -103,6 +107,12 @@ func Test_collector_Describe(t *testing.T) {
 			[]string{"device", "collector", "success"},
 			nil,
 		),
+		prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "collector", "unsupported"),
+			"whether the collector was skipped because the device doesn't support it (unsupported = 1)",
+			[]string{"device", "collector"},
+			nil,
+		),
 	}, gotDescriptions)
 }
 
@@ -151,6 +161,46 @@ func Test_collector_Collect(t *testing.T) {
 		mc = minimock.NewController(t)
 		routerOSClientMock = routerosMocks.NewClientMock(mc)
 	}
+	setProbeMocks := func() {
+		routerOSClientMock.RunMock.Set(func(sentence ...string) (*routerosAPI.Reply, error) {
+			switch sentence[0] {
+			case "/system/resource/print":
+				r.Equal([]string{"/system/resource/print", "=.proplist=version,architecture-name,board-name"}, sentence)
+				return &routerosAPI.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"version": "7.12.1 (stable)", "architecture-name": "arm64", "board-name": "RB5009UG+S+"}},
+					},
+				}, nil
+			case "/system/package/print":
+				r.Equal([]string{"/system/package/print", "?disabled=false", "=.proplist=name"}, sentence)
+				return &routerosAPI.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"name": "routeros"}},
+						{Map: map[string]string{"name": "wifi-qcom"}},
+					},
+				}, nil
+			case "/system/clock/print":
+				r.Equal([]string{"/system/clock/print", "=.proplist=time-zone-name"}, sentence)
+				return &routerosAPI.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"time-zone-name": "Europe/Berlin"}},
+					},
+				}, nil
+			default:
+				r.FailNow("unexpected command")
+				return nil, nil
+			}
+		})
+	}
+	unsupportedCollector := &supportCheckerStub{
+		FeatureCollectorMock: mocks.NewFeatureCollectorMock(mc),
+		pkg:                  "wireless",
+	}
+	unsupportedCollector.NameMock.Return("wlan")
+	supportedCollector := &supportCheckerStub{
+		FeatureCollectorMock: featureCollectorMock,
+		pkg:                  "routeros",
+	}
 
 	testCases := []struct {
 		name     string
@@ -177,6 +227,7 @@ func Test_collector_Collect(t *testing.T) {
 					return ch
 				})
 				routerOSClientMock.CloseMock.Return()
+				setProbeMocks()
 			},
 			want: []prometheus.Metric{
 				prometheus.MustNewConstMetric(
@@ -252,6 +303,7 @@ func Test_collector_Collect(t *testing.T) {
 					return ch
 				})
 				routerOSClientMock.CloseMock.Return()
+				setProbeMocks()
 			},
 			want: []prometheus.Metric{
 				prometheus.MustNewConstMetric(
@@ -366,6 +418,7 @@ func Test_collector_Collect(t *testing.T) {
 					return ch
 				})
 				routerOSClientMock.CloseMock.Return()
+				setProbeMocks()
 			},
 			want: []prometheus.Metric{
 				prometheus.MustNewConstMetric(
@@ -448,6 +501,48 @@ func Test_collector_Collect(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "unsupported collector",
+			devices: []*Device{
+				{
+					Name:    "test1",
+					Address: "192.168.1.1",
+				},
+			},
+			opts: []Option{
+				WithCustomClientCreatorFunc(func(device *Device) (routeros.Client, error) {
+					return routerOSClientMock, nil
+				}),
+				WithCollectors(unsupportedCollector, supportedCollector),
+			},
+			setMocks: func() {
+				routerOSClientMock.AsyncMock.Set(func() <-chan error {
+					ch := make(chan error)
+					defer close(ch)
+					return ch
+				})
+				routerOSClientMock.CloseMock.Return()
+				setProbeMocks()
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(scrapeDurationMetricDescription, prometheus.GaugeValue, 2.0, "test1", "connect", "true"),
+				prometheus.MustNewConstMetric(scrapeDurationMetricDescription, prometheus.GaugeValue, 2.0, "test1", "collect", "true"),
+				prometheus.MustNewConstMetric(
+					prometheus.NewDesc(
+						prometheus.BuildFQName(namespace, "collector", "unsupported"),
+						"whether the collector was skipped because the device doesn't support it (unsupported = 1)",
+						[]string{"device", "collector"},
+						nil,
+					),
+					prometheus.GaugeValue,
+					1,
+					"test1",
+					"wlan",
+				),
+				prometheus.MustNewConstMetric(collectorUnsupportedMetricDescription, prometheus.GaugeValue, 0, "test1", "testCollector"),
+				prometheus.MustNewConstMetric(collectorDurationMetricDescription, prometheus.GaugeValue, 2.0, "test1", "testCollector", "true"),
+			},
+		},
 		{
 			name:    "skipped device with DNS lookup",
 			devices: validDevices,
@@ -466,6 +561,7 @@ func Test_collector_Collect(t *testing.T) {
 					return ch
 				})
 				routerOSClientMock.CloseMock.Return()
+				setProbeMocks()
 			},
 			want: []prometheus.Metric{
 				prometheus.MustNewConstMetric(
@@ -551,3 +647,177 @@ func Test_collector_Collect(t *testing.T) {
 		})
 	}
 }
+
+// supportCheckerStub - feature collector, which requires the given package
+type supportCheckerStub struct {
+	*mocks.FeatureCollectorMock
+	pkg string
+}
+
+func (s *supportCheckerStub) Supported(caps *context.Capabilities) bool {
+	return caps.MayHavePackage(s.pkg)
+}
+
+func Test_collector_relabelMetrics(t *testing.T) {
+	r := require.New(t)
+
+	mustNewRule := func(action string, sourceLabels []string, regex, targetLabel, replacement string) *relabel.Rule {
+		rule, err := relabel.NewRule(action, sourceLabels, "", regex, targetLabel, replacement)
+		r.NoError(err)
+		return rule
+	}
+
+	desc := metrics.BuildMetricDescription("interface", "rx_byte", "number of rx bytes on interface", []string{"interface", "comment"})
+	collected := []prometheus.Metric{
+		prometheus.MustNewConstMetric(desc, prometheus.CounterValue, 100, "ether1", "uplink"),
+		prometheus.MustNewConstMetric(desc, prometheus.CounterValue, 200, "ether2", "lab"),
+	}
+
+	testCases := []struct {
+		name   string
+		opts   []Option
+		device *Device
+		want   []prometheus.Metric
+	}{
+		{
+			name:   "no rules",
+			device: &Device{Name: "test1"},
+			want:   collected,
+		},
+		{
+			name: "app and device level rules",
+			opts: []Option{
+				WithRelabelRules(mustNewRule("labeldrop", nil, "comment", "", "")),
+			},
+			device: &Device{
+				Name: "test1",
+				RelabelRules: []*relabel.Rule{
+					mustNewRule("drop", []string{"interface"}, "ether2", "", ""),
+				},
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					prometheus.NewDesc("mikrotik_interface_rx_byte", "number of rx bytes on interface", []string{"interface"}, nil),
+					prometheus.CounterValue, 100, "ether1",
+				),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			co, ok := NewMikrotikCollector(nil, tc.opts...).(*routerosCollector)
+			r.True(ok)
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for m := range ch {
+					got = append(got, m)
+				}
+			}()
+
+			metricsChan, waitRelabel := co.relabelMetrics(tc.device, ch)
+			for _, m := range collected {
+				metricsChan <- m
+			}
+			waitRelabel()
+			close(ch)
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
+
+func Test_mergeCollectors(t *testing.T) {
+	r := require.New(t)
+
+	mc := minimock.NewController(t)
+	newCollector := func(name string) FeatureCollector {
+		co := mocks.NewFeatureCollectorMock(mc)
+		co.NameMock.Return(name)
+		return co
+	}
+
+	appInterface := newCollector("interface")
+	appResource := newCollector("resource")
+	deviceInterface := newCollector("interface")
+	deviceDHCP := newCollector("dhcp_lease")
+
+	// collectors are compared by identity, as mocks of the same name are deeply equal
+	requireSame := func(want, got []FeatureCollector) {
+		r.Len(got, len(want))
+		for i := range want {
+			r.Same(want[i], got[i])
+		}
+	}
+
+	requireSame([]FeatureCollector{appInterface, appResource}, mergeCollectors([]FeatureCollector{appInterface, appResource}, nil))
+	requireSame(
+		[]FeatureCollector{appResource, deviceInterface, deviceDHCP},
+		mergeCollectors([]FeatureCollector{appInterface, appResource}, []FeatureCollector{deviceInterface, deviceDHCP}),
+	)
+}
+
+func Test_loadLocation(t *testing.T) {
+	r := require.New(t)
+
+	d := &Device{Name: "test1"}
+
+	loc := loadLocation(d, "Europe/Berlin")
+	r.NotNil(loc)
+	r.Equal("Europe/Berlin", loc.String())
+
+	r.Nil(loadLocation(d, ""))
+	r.Nil(loadLocation(d, "manual"))
+	r.Nil(loadLocation(d, "Mars/Olympus_Mons"))
+}
+
+func Test_collector_deviceCapabilities(t *testing.T) {
+	r := require.New(t)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer func(f func() time.Time) { timeNowUTC = f }(timeNowUTC)
+	timeNowUTC = func() time.Time {
+		return now
+	}
+
+	var probeErr error
+	routerOSClientMock := routerosMocks.NewClientMock(t)
+	routerOSClientMock.RunMock.Set(func(sentence ...string) (*routerosAPI.Reply, error) {
+		switch sentence[0] {
+		case "/system/resource/print":
+			return &routerosAPI.Reply{
+				Re: []*proto.Sentence{{Map: map[string]string{"version": "7.12.1 (stable)"}}},
+			}, nil
+		case "/system/package/print":
+			return &routerosAPI.Reply{}, probeErr
+		default:
+			return &routerosAPI.Reply{}, nil
+		}
+	})
+
+	co, ok := NewMikrotikCollector(nil).(*routerosCollector)
+	r.True(ok)
+	d := &Device{Name: "test1"}
+
+	probeErr = errors.New("some probe error")
+	r.Equal("7.12.1 (stable)", co.deviceCapabilities(d, routerOSClientMock).Version)
+	r.Equal(uint64(3), routerOSClientMock.RunAfterCounter())
+
+	// capabilities probed with failures are probed again on the next scrape
+	probeErr = nil
+	caps := co.deviceCapabilities(d, routerOSClientMock)
+	r.Equal(uint64(6), routerOSClientMock.RunAfterCounter())
+
+	now = now.Add(capabilitiesTTL - time.Second)
+	r.Same(caps, co.deviceCapabilities(d, routerOSClientMock))
+	r.Equal(uint64(6), routerOSClientMock.RunAfterCounter())
+
+	now = now.Add(time.Second)
+	r.NotSame(caps, co.deviceCapabilities(d, routerOSClientMock))
+	r.Equal(uint64(9), routerOSClientMock.RunAfterCounter())
+}
@@ -0,0 +1,74 @@
+package context
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Capabilities - represents device properties probed once per connection, fields are empty if probing failed
+type Capabilities struct {
+	// Version - RouterOS version, e.g. 7.12.1 (stable)
+	Version string
+	// Architecture - CPU architecture, e.g. arm64
+	Architecture string
+	// Board - board name, e.g. RB5009UG+S+
+	Board string
+	// Packages - names of the enabled packages
+	Packages []string
+	// TimeZone - time zone of the device clock, RouterOS reports dates like last-link-up-time in local time
+	TimeZone *time.Location
+}
+
+// MajorVersion - returns the RouterOS major version or 0 if unknown
+func (c *Capabilities) MajorVersion() int {
+	if c == nil {
+		return 0
+	}
+
+	major, _, _ := strings.Cut(c.Version, ".")
+	v, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+
+	return v
+}
+
+// MayHavePackage - reports false only if the packages are known and the given one isn't enabled
+func (c *Capabilities) MayHavePackage(name string) bool {
+	if c == nil || len(c.Packages) == 0 {
+		return true
+	}
+
+	for _, p := range c.Packages {
+		if p == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MayBeRouterBoard - reports false only if the device is known to be a CHR or x86 installation,
+// which has no RouterBOARD hardware like PoE, SFP cages, LTE modems or health sensors
+func (c *Capabilities) MayBeRouterBoard() bool {
+	if c == nil {
+		return true
+	}
+
+	if c.Board == "CHR" || c.Board == "x86" {
+		return false
+	}
+
+	return c.Architecture != "x86" && c.Architecture != "x86_64"
+}
+
+// Location - returns the time zone of the device clock or UTC if unknown
+func (c *Capabilities) Location() *time.Location {
+	if c == nil || c.TimeZone == nil {
+		return time.UTC
+	}
+
+	return c.TimeZone
+}
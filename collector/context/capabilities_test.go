@@ -0,0 +1,78 @@
+package context
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities_MajorVersion(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		name string
+		caps *Capabilities
+		want int
+	}{
+		{name: "v7", caps: &Capabilities{Version: "7.12.1 (stable)"}, want: 7},
+		{name: "v6", caps: &Capabilities{Version: "6.49.10 (long-term)"}, want: 6},
+		{name: "unknown", caps: &Capabilities{}, want: 0},
+		{name: "nil", want: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r.Equal(tc.want, tc.caps.MajorVersion())
+		})
+	}
+}
+
+func TestCapabilities_MayHavePackage(t *testing.T) {
+	r := require.New(t)
+
+	caps := &Capabilities{Packages: []string{"routeros", "wireless"}}
+	r.True(caps.MayHavePackage("wireless"))
+	r.False(caps.MayHavePackage("lte"))
+
+	r.True((&Capabilities{}).MayHavePackage("lte"))
+
+	var nilCaps *Capabilities
+	r.True(nilCaps.MayHavePackage("lte"))
+}
+
+func TestCapabilities_MayBeRouterBoard(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		name string
+		caps *Capabilities
+		want bool
+	}{
+		{name: "routerboard", caps: &Capabilities{Architecture: "arm64", Board: "RB5009UG+S+"}, want: true},
+		{name: "chr", caps: &Capabilities{Architecture: "arm64", Board: "CHR"}, want: false},
+		{name: "x86 v6", caps: &Capabilities{Architecture: "x86", Board: "x86"}, want: false},
+		{name: "x86_64", caps: &Capabilities{Architecture: "x86_64", Board: "Standard PC (i440FX + PIIX, 1996)"}, want: false},
+		{name: "unknown", caps: &Capabilities{}, want: true},
+		{name: "nil", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r.Equal(tc.want, tc.caps.MayBeRouterBoard())
+		})
+	}
+}
+
+func TestCapabilities_Location(t *testing.T) {
+	r := require.New(t)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	r.NoError(err)
+
+	r.Equal(berlin, (&Capabilities{TimeZone: berlin}).Location())
+	r.Equal(time.UTC, (&Capabilities{}).Location())
+
+	var nilCaps *Capabilities
+	r.Equal(time.UTC, nilCaps.Location())
+}
@@ -9,7 +9,10 @@ import (
 // Context - represents context, which is passed to feature collectors
 type Context struct {
 	RouterOSClient routeros.Client
-	MetricsChan    chan<- prometheus.Metric
-	DeviceName     string
-	DeviceAddress  string
+	// NewRouterOSClient - opens a dedicated connection to the device, which has to be closed by the caller
+	NewRouterOSClient func() (routeros.Client, error)
+	MetricsChan       chan<- prometheus.Metric
+	DeviceName        string
+	DeviceAddress     string
+	Capabilities      *Capabilities
 }
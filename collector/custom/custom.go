@@ -0,0 +1,298 @@
+package custom
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/parsers"
+)
+
+// Parsers supported for metric values
+const (
+	ParserNumber     = "number"
+	ParserDuration   = "duration"
+	ParserBool       = "bool"
+	ParserPairFirst  = "pair_first"
+	ParserPairSecond = "pair_second"
+)
+
+// Value types supported for metrics
+const (
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+)
+
+type (
+	// Definition - describes a custom collector
+	Definition struct {
+		// Name - collector name, used as metric prefix
+		Name string
+		// Command - RouterOS command to run, e.g. /system/ntp/client/print
+		Command string
+		// Filters - property values the returned entries have to match, optional
+		Filters map[string]string
+		// Labels - properties exported as labels, optional
+		Labels []*Label
+		// Metrics - properties exported as metrics
+		Metrics []*Metric
+	}
+
+	// Label - describes a property exported as label
+	Label struct {
+		// Property - RouterOS property name
+		Property string
+		// Name - label name, optional, defaults to property with dashes replaced by underscores
+		Name string
+	}
+
+	// Metric - describes a property exported as metric
+	Metric struct {
+		// Property - RouterOS property name
+		Property string
+		// Name - metric name, optional, defaults to property with dashes replaced by underscores
+		Name string
+		// Help - metric help text, optional
+		Help string
+		// Type - gauge or counter, optional, defaults to gauge
+		Type string
+		// Parser - number, duration, bool, pair_first or pair_second, optional, defaults to number
+		Parser string
+	}
+
+	metricDescription struct {
+		*metrics.MetricDescription
+		property string
+		parser   string
+	}
+
+	customCollector struct {
+		name               string
+		command            string
+		queries            []string
+		properties         []string
+		labelProperties    []string
+		metricDescriptions []*metricDescription
+	}
+)
+
+// NewCollector - custom collector constructor, validates the definition
+func NewCollector(def *Definition) (*customCollector, error) {
+	if len(def.Name) == 0 {
+		return nil, fmt.Errorf("custom collector name is required")
+	}
+
+	if len(def.Command) == 0 {
+		return nil, fmt.Errorf("custom collector %s: command is required", def.Name)
+	}
+
+	if len(def.Metrics) == 0 {
+		return nil, fmt.Errorf("custom collector %s: at least one metric is required", def.Name)
+	}
+
+	c := &customCollector{
+		name:    def.Name,
+		command: def.Command,
+	}
+
+	filterProperties := make([]string, 0, len(def.Filters))
+	for p := range def.Filters {
+		filterProperties = append(filterProperties, p)
+	}
+	sort.Strings(filterProperties)
+	for _, p := range filterProperties {
+		c.queries = append(c.queries, fmt.Sprintf("?%s=%s", p, def.Filters[p]))
+	}
+
+	labelNames := []string{"name", "address"}
+	for _, l := range def.Labels {
+		labelName := nameOrProperty(l.Name, l.Property)
+		if err := validateLabelName(labelName, labelNames); err != nil {
+			return nil, fmt.Errorf("custom collector %s: label of property %s: %w", def.Name, l.Property, err)
+		}
+
+		c.addProperty(l.Property)
+		c.labelProperties = append(c.labelProperties, l.Property)
+		labelNames = append(labelNames, labelName)
+	}
+
+	metricNames := make(map[string]bool, len(def.Metrics))
+	for _, m := range def.Metrics {
+		d, err := c.buildMetricDescription(m, labelNames)
+		if err != nil {
+			return nil, fmt.Errorf("custom collector %s: %w", def.Name, err)
+		}
+
+		name := nameOrProperty(m.Name, m.Property)
+		if metricNames[name] {
+			return nil, fmt.Errorf("custom collector %s: duplicate metric name %q", def.Name, name)
+		}
+		metricNames[name] = true
+
+		c.addProperty(m.Property)
+		c.metricDescriptions = append(c.metricDescriptions, d)
+	}
+
+	return c, nil
+}
+
+func (c *customCollector) buildMetricDescription(m *Metric, labelNames []string) (*metricDescription, error) {
+	var valueType prometheus.ValueType
+	switch m.Type {
+	case "", TypeGauge:
+		valueType = prometheus.GaugeValue
+	case TypeCounter:
+		valueType = prometheus.CounterValue
+	default:
+		return nil, fmt.Errorf("unknown type %q of metric %s", m.Type, m.Property)
+	}
+
+	parser := m.Parser
+	switch parser {
+	case "":
+		parser = ParserNumber
+	case ParserNumber, ParserDuration, ParserBool, ParserPairFirst, ParserPairSecond:
+	default:
+		return nil, fmt.Errorf("unknown parser %q of metric %s", m.Parser, m.Property)
+	}
+
+	name := nameOrProperty(m.Name, m.Property)
+	if fqName := prometheus.BuildFQName("mikrotik", c.name, name); !model.IsValidMetricName(model.LabelValue(fqName)) {
+		return nil, fmt.Errorf("invalid metric name %q of metric %s", fqName, m.Property)
+	}
+
+	help := m.Help
+	if len(help) == 0 {
+		help = fmt.Sprintf("%s from %s", m.Property, c.command)
+	}
+
+	return &metricDescription{
+		MetricDescription: &metrics.MetricDescription{
+			Desc:      metrics.BuildMetricDescription(c.name, name, help, labelNames),
+			ValueType: valueType,
+		},
+		property: m.Property,
+		parser:   parser,
+	}, nil
+}
+
+func (c *customCollector) addProperty(property string) {
+	for _, p := range c.properties {
+		if p == property {
+			return
+		}
+	}
+
+	c.properties = append(c.properties, property)
+}
+
+func (c *customCollector) Name() string {
+	return c.name
+}
+
+func (c *customCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range c.metricDescriptions {
+		ch <- d.Desc
+	}
+}
+
+func (c *customCollector) Collect(ctx *context.Context) error {
+	sentence := make([]string, 0, len(c.queries)+2)
+	sentence = append(sentence, c.command)
+	sentence = append(sentence, c.queries...)
+	sentence = append(sentence, "=.proplist="+strings.Join(c.properties, ","))
+
+	reply, err := ctx.RouterOSClient.Run(sentence...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", c.command, err)
+	}
+
+	for _, re := range reply.Re {
+		c.collectForStat(re, ctx)
+	}
+
+	return nil
+}
+
+func (c *customCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress}
+	for _, p := range c.labelProperties {
+		labelValues = append(labelValues, re.Map[p])
+	}
+
+	for _, d := range c.metricDescriptions {
+		value := re.Map[d.property]
+		if len(value) == 0 {
+			continue
+		}
+
+		v, err := parseValue(d.parser, value)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"property":  d.property,
+				"value":     value,
+				"error":     err,
+			}).Error("failed to parse custom metric value")
+			continue
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(d.Desc, d.ValueType, v, labelValues...)
+	}
+}
+
+func parseValue(parser, value string) (float64, error) {
+	switch parser {
+	case ParserDuration:
+		return parsers.ParseDuration(value)
+	case ParserBool:
+		if value == "true" || value == "yes" {
+			return 1, nil
+		}
+		return 0, nil
+	case ParserPairFirst:
+		v, _, err := parsers.ParseCommaSeparatedValuesToFloat64(value)
+		return v, err
+	case ParserPairSecond:
+		_, v, err := parsers.ParseCommaSeparatedValuesToFloat64(value)
+		return v, err
+	default:
+		return strconv.ParseFloat(value, 64)
+	}
+}
+
+// validateLabelName - checks that a label name is valid and doesn't clash with the device or other labels
+func validateLabelName(name string, labelNames []string) error {
+	if !model.LabelName(name).IsValid() {
+		return fmt.Errorf("invalid label name %q", name)
+	}
+
+	if name == "name" || name == "address" {
+		return fmt.Errorf("label name %q is reserved for the device", name)
+	}
+
+	for _, l := range labelNames {
+		if l == name {
+			return fmt.Errorf("duplicate label name %q", name)
+		}
+	}
+
+	return nil
+}
+
+func nameOrProperty(name, property string) string {
+	if len(name) != 0 {
+		return name
+	}
+
+	return strings.ReplaceAll(property, "-", "_")
+}
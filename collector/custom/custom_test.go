@@ -0,0 +1,313 @@
+package custom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+var testDefinition = &Definition{
+	Name:    "ntp_client",
+	Command: "/system/ntp/client/print",
+	Filters: map[string]string{
+		"enabled": "true",
+	},
+	Labels: []*Label{
+		{Property: "status"},
+		{Property: "synced-server", Name: "server"},
+	},
+	Metrics: []*Metric{
+		{Property: "freq-drift", Help: "ntp frequency drift in ppm"},
+		{Property: "synced-stratum", Name: "stratum"},
+		{Property: "system-offset", Name: "offset_seconds", Parser: ParserDuration},
+		{Property: "enabled", Parser: ParserBool},
+		{Property: "packets", Name: "tx_packets", Type: TypeCounter, Parser: ParserPairFirst},
+		{Property: "packets", Name: "rx_packets", Type: TypeCounter, Parser: ParserPairSecond},
+	},
+}
+
+var testLabelNames = []string{"name", "address", "status", "server"}
+
+func TestNewCollector(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		name    string
+		def     *Definition
+		errWant string
+	}{
+		{
+			name: "valid",
+			def:  testDefinition,
+		},
+		{
+			name:    "missing name",
+			def:     &Definition{Command: "/ip/cloud/print"},
+			errWant: "custom collector name is required",
+		},
+		{
+			name:    "missing command",
+			def:     &Definition{Name: "cloud"},
+			errWant: "custom collector cloud: command is required",
+		},
+		{
+			name:    "missing metrics",
+			def:     &Definition{Name: "cloud", Command: "/ip/cloud/print"},
+			errWant: "custom collector cloud: at least one metric is required",
+		},
+		{
+			name: "unknown type",
+			def: &Definition{
+				Name:    "cloud",
+				Command: "/ip/cloud/print",
+				Metrics: []*Metric{{Property: "ddns-enabled", Type: "histogram"}},
+			},
+			errWant: "custom collector cloud: unknown type \"histogram\" of metric ddns-enabled",
+		},
+		{
+			name: "unknown parser",
+			def: &Definition{
+				Name:    "cloud",
+				Command: "/ip/cloud/print",
+				Metrics: []*Metric{{Property: "ddns-enabled", Parser: "yesno"}},
+			},
+			errWant: "custom collector cloud: unknown parser \"yesno\" of metric ddns-enabled",
+		},
+		{
+			name: "invalid metric name",
+			def: &Definition{
+				Name:    "bgp",
+				Command: "/routing/bgp/session/print",
+				Metrics: []*Metric{{Property: "local.messages"}},
+			},
+			errWant: "custom collector bgp: invalid metric name \"mikrotik_bgp_local.messages\" of metric local.messages",
+		},
+		{
+			name: "invalid collector name",
+			def: &Definition{
+				Name:    "ntp-client",
+				Command: "/system/ntp/client/print",
+				Metrics: []*Metric{{Property: "freq-drift"}},
+			},
+			errWant: "custom collector ntp-client: invalid metric name \"mikrotik_ntp-client_freq_drift\" of metric freq-drift",
+		},
+		{
+			name: "duplicate metric name",
+			def: &Definition{
+				Name:    "cloud",
+				Command: "/ip/cloud/print",
+				Metrics: []*Metric{{Property: "ddns-enabled"}, {Property: "enabled", Name: "ddns_enabled"}},
+			},
+			errWant: "custom collector cloud: duplicate metric name \"ddns_enabled\"",
+		},
+		{
+			name: "invalid label name",
+			def: &Definition{
+				Name:    "bgp",
+				Command: "/routing/bgp/session/print",
+				Labels:  []*Label{{Property: "local.role"}},
+				Metrics: []*Metric{{Property: "prefix-count"}},
+			},
+			errWant: "custom collector bgp: label of property local.role: invalid label name \"local.role\"",
+		},
+		{
+			name: "reserved name label",
+			def: &Definition{
+				Name:    "bgp",
+				Command: "/routing/bgp/session/print",
+				Labels:  []*Label{{Property: "name"}},
+				Metrics: []*Metric{{Property: "prefix-count"}},
+			},
+			errWant: "custom collector bgp: label of property name: label name \"name\" is reserved for the device",
+		},
+		{
+			name: "reserved address label",
+			def: &Definition{
+				Name:    "bgp",
+				Command: "/routing/bgp/session/print",
+				Labels:  []*Label{{Property: "remote.address", Name: "address"}},
+				Metrics: []*Metric{{Property: "prefix-count"}},
+			},
+			errWant: "custom collector bgp: label of property remote.address: label name \"address\" is reserved for the device",
+		},
+		{
+			name: "duplicate label",
+			def: &Definition{
+				Name:    "bgp",
+				Command: "/routing/bgp/session/print",
+				Labels:  []*Label{{Property: "remote.as", Name: "as"}, {Property: "local.as", Name: "as"}},
+				Metrics: []*Metric{{Property: "prefix-count"}},
+			},
+			errWant: "custom collector bgp: label of property local.as: duplicate label name \"as\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewCollector(tc.def)
+			if len(tc.errWant) != 0 {
+				r.EqualError(err, tc.errWant)
+			} else {
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func Test_customCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c, err := NewCollector(testDefinition)
+	r.NoError(err)
+
+	r.Equal("ntp_client", c.Name())
+}
+
+func Test_customCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c, err := NewCollector(testDefinition)
+	r.NoError(err)
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription("ntp_client", "freq_drift", "ntp frequency drift in ppm", testLabelNames),
+		metrics.BuildMetricDescription("ntp_client", "stratum", "synced-stratum from /system/ntp/client/print", testLabelNames),
+		metrics.BuildMetricDescription("ntp_client", "offset_seconds", "system-offset from /system/ntp/client/print", testLabelNames),
+		metrics.BuildMetricDescription("ntp_client", "enabled", "enabled from /system/ntp/client/print", testLabelNames),
+		metrics.BuildMetricDescription("ntp_client", "tx_packets", "packets from /system/ntp/client/print", testLabelNames),
+		metrics.BuildMetricDescription("ntp_client", "rx_packets", "packets from /system/ntp/client/print", testLabelNames),
+	}, got)
+}
+
+func Test_customCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c, err := NewCollector(testDefinition)
+	r.NoError(err)
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/system/ntp/client/print",
+					"?enabled=true",
+					"=.proplist=status,synced-server,freq-drift,synced-stratum,system-offset,enabled,packets",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"status":         "synchronized",
+								"synced-server":  "162.159.200.1",
+								"freq-drift":     "-3.112",
+								"synced-stratum": "a4",
+								"system-offset":  "1s",
+								"enabled":        "true",
+								"packets":        "10,8",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription("ntp_client", "freq_drift", "ntp frequency drift in ppm", testLabelNames),
+					prometheus.GaugeValue, -3.112, "device", "address", "synchronized", "162.159.200.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription("ntp_client", "offset_seconds", "system-offset from /system/ntp/client/print", testLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "synchronized", "162.159.200.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription("ntp_client", "enabled", "enabled from /system/ntp/client/print", testLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "synchronized", "162.159.200.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription("ntp_client", "tx_packets", "packets from /system/ntp/client/print", testLabelNames),
+					prometheus.CounterValue, 10, "device", "address", "synchronized", "162.159.200.1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription("ntp_client", "rx_packets", "packets from /system/ntp/client/print", testLabelNames),
+					prometheus.CounterValue, 8, "device", "address", "synchronized", "162.159.200.1",
+				),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/system/ntp/client/print",
+					"?enabled=true",
+					"=.proplist=status,synced-server,freq-drift,synced-stratum,system-offset,enabled,packets",
+				}...).Then(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch /system/ntp/client/print: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
@@ -15,18 +16,61 @@ import (
 )
 
 var (
-	properties        = []string{"active-mac-address", "server", "status", "expires-after", "active-address", "host-name"}
+	properties        = []string{"active-mac-address", "server", "status", "expires-after", "active-address", "host-name", "dynamic"}
+	statuses          = []string{"bound", "waiting", "offered", "busy"}
 	metricDescription = metrics.BuildMetricDescription(prefix, "expires_after", "dhcp lease expires after seconds",
 		[]string{"name", "address", "active_mac_address", "server", "status", "active_address", "hostname"},
 	)
+	countMetricDescription = metrics.BuildMetricDescription(prefix, "count", "number of dhcp leases",
+		[]string{"name", "address", "server", "status"},
+	)
+	typeCountMetricDescription = metrics.BuildMetricDescription(prefix, "type_count", "number of static and dynamic dhcp leases",
+		[]string{"name", "address", "server", "type"},
+	)
+	poolSizeMetricDescription = metrics.BuildMetricDescription(serverPrefix, "pool_size", "number of addresses in dhcp server address pool",
+		[]string{"name", "address", "server", "pool"},
+	)
+	utilizationMetricDescription = metrics.BuildMetricDescription(serverPrefix, "utilization_ratio", "ratio of bound dhcp leases within the address pool to its size (0-1)",
+		[]string{"name", "address", "server", "pool"},
+	)
 )
 
-const prefix = "dhcp_lease"
+const (
+	prefix       = "dhcp_lease"
+	serverPrefix = "dhcp_server"
+)
+
+type dhcpLeaseCollector struct {
+	leaseSeries bool
+	seriesLimit int
+}
 
-type dhcpLeaseCollector struct{}
+// Option - represents a function on dhcp lease collector instance
+type Option func(*dhcpLeaseCollector)
 
-func NewCollector() *dhcpLeaseCollector {
-	return &dhcpLeaseCollector{}
+// WithoutLeaseSeries - drops per lease metrics and exports only the per server aggregates
+func WithoutLeaseSeries() Option {
+	return func(c *dhcpLeaseCollector) {
+		c.leaseSeries = false
+	}
+}
+
+// WithSeriesLimit - exports only lease counts when a device has more leases than limit
+func WithSeriesLimit(limit int) Option {
+	return func(c *dhcpLeaseCollector) {
+		c.seriesLimit = limit
+	}
+}
+
+func NewCollector(opts ...Option) *dhcpLeaseCollector {
+	c := &dhcpLeaseCollector{
+		leaseSeries: true,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
 }
 
 func (c *dhcpLeaseCollector) Name() string {
@@ -34,32 +78,172 @@ func (c *dhcpLeaseCollector) Name() string {
 }
 
 func (c *dhcpLeaseCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- metricDescription
+	ch <- countMetricDescription
+	ch <- typeCountMetricDescription
+	ch <- poolSizeMetricDescription
+	ch <- utilizationMetricDescription
+	if c.leaseSeries {
+		ch <- metricDescription
+		if c.seriesLimit > 0 {
+			ch <- metrics.SeriesTruncatedMetricDescription
+		}
+	}
 }
 
 func (c *dhcpLeaseCollector) Collect(ctx *context.Context) error {
-	stats, err := c.fetch(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch dhcp leases: %w", err)
+	var (
+		leases  []*proto.Sentence
+		servers []*proto.Sentence
+		pools   []*proto.Sentence
+	)
+
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		reply, err := ctx.RouterOSClient.Run(
+			"/ip/dhcp-server/lease/print",
+			"=.proplist="+strings.Join(properties, ","),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to fetch dhcp leases: %w", err)
+		}
+
+		leases = reply.Re
+		return nil
+	})
+
+	eg.Go(func() error {
+		reply, err := ctx.RouterOSClient.Run(
+			"/ip/dhcp-server/print",
+			"=.proplist=name,address-pool",
+		)
+		if err != nil {
+			return fmt.Errorf("failed to fetch dhcp servers: %w", err)
+		}
+
+		servers = reply.Re
+		return nil
+	})
+
+	eg.Go(func() error {
+		reply, err := ctx.RouterOSClient.Run(
+			"/ip/pool/print",
+			"=.proplist=name,ranges",
+		)
+		if err != nil {
+			return fmt.Errorf("failed to fetch ip pools: %w", err)
+		}
+
+		pools = reply.Re
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	c.collectCounts(ctx, leases, servers)
+	c.collectUtilization(ctx, leases, servers, pools)
+
+	if !c.leaseSeries || metrics.CheckSeriesLimit(ctx, c.Name(), c.seriesLimit, len(leases)) {
+		return nil
 	}
 
-	for _, re := range stats {
+	for _, re := range leases {
 		c.collectMetric(ctx, re)
 	}
 
 	return nil
 }
 
-func (c *dhcpLeaseCollector) fetch(ctx *context.Context) ([]*proto.Sentence, error) {
-	reply, err := ctx.RouterOSClient.Run(
-		"/ip/dhcp-server/lease/print",
-		"=.proplist="+strings.Join(properties, ","),
-	)
-	if err != nil {
-		return nil, err
+func (c *dhcpLeaseCollector) collectCounts(ctx *context.Context, leases, servers []*proto.Sentence) {
+	type countKey struct {
+		server string
+		value  string
+	}
+
+	statusCounts := make(map[countKey]float64)
+	typeCounts := make(map[countKey]float64)
+	// known statuses and types are exported for every server, so that a missing state reads as 0
+	for _, re := range servers {
+		for _, s := range statuses {
+			statusCounts[countKey{server: re.Map["name"], value: s}] = 0
+		}
+		typeCounts[countKey{server: re.Map["name"], value: "static"}] = 0
+		typeCounts[countKey{server: re.Map["name"], value: "dynamic"}] = 0
+	}
+
+	for _, re := range leases {
+		statusCounts[countKey{server: re.Map["server"], value: re.Map["status"]}]++
+		typeCounts[countKey{server: re.Map["server"], value: leaseType(re)}]++
+	}
+
+	for k, v := range statusCounts {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(countMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, k.server, k.value,
+		)
+	}
+
+	for k, v := range typeCounts {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(typeCountMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, k.server, k.value,
+		)
+	}
+}
+
+func (c *dhcpLeaseCollector) collectUtilization(ctx *context.Context, leases, servers, pools []*proto.Sentence) {
+	poolRanges := make(map[string]string, len(pools))
+	for _, re := range pools {
+		poolRanges[re.Map["name"]] = re.Map["ranges"]
+	}
+
+	for _, re := range servers {
+		server, pool := re.Map["name"], re.Map["address-pool"]
+		ranges, found := poolRanges[pool]
+		if !found {
+			// servers without an address pool only hand out static leases
+			continue
+		}
+
+		r, err := parsers.ParseIPRanges(ranges)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"server":    server,
+				"pool":      pool,
+				"value":     ranges,
+				"error":     err,
+			}).Error("failed to parse dhcp server pool size")
+			continue
+		}
+		size := r.Size()
+		if size == 0 {
+			continue
+		}
+
+		// static leases within the pool take addresses as well, so all bound leases are counted
+		var bound float64
+		for _, l := range leases {
+			if l.Map["server"] == server && l.Map["status"] == "bound" && r.Contains(l.Map["active-address"]) {
+				bound++
+			}
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(poolSizeMetricDescription, prometheus.GaugeValue, size,
+			ctx.DeviceName, ctx.DeviceAddress, server, pool,
+		)
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(utilizationMetricDescription, prometheus.GaugeValue, bound/size,
+			ctx.DeviceName, ctx.DeviceAddress, server, pool,
+		)
+	}
+}
+
+func leaseType(re *proto.Sentence) string {
+	if re.Map["dynamic"] == "true" {
+		return "dynamic"
 	}
 
-	return reply.Re, nil
+	return "static"
 }
 
 func (c *dhcpLeaseCollector) collectMetric(ctx *context.Context, re *proto.Sentence) {
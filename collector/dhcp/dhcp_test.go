@@ -25,99 +25,234 @@ func Test_dhcpLeaseCollector_Name(t *testing.T) {
 func Test_dhcpLeaseCollector_Describe(t *testing.T) {
 	r := require.New(t)
 
-	c := NewCollector()
+	testCases := []struct {
+		name string
+		opts []Option
+		want []*prometheus.Desc
+	}{
+		{
+			name: "without lease series",
+			opts: []Option{WithoutLeaseSeries()},
+			want: []*prometheus.Desc{
+				metrics.BuildMetricDescription(prefix, "count", "number of dhcp leases",
+					[]string{"name", "address", "server", "status"},
+				),
+				metrics.BuildMetricDescription(prefix, "type_count", "number of static and dynamic dhcp leases",
+					[]string{"name", "address", "server", "type"},
+				),
+				metrics.BuildMetricDescription("dhcp_server", "pool_size", "number of addresses in dhcp server address pool",
+					[]string{"name", "address", "server", "pool"},
+				),
+				metrics.BuildMetricDescription("dhcp_server", "utilization_ratio", "ratio of bound dhcp leases within the address pool to its size (0-1)",
+					[]string{"name", "address", "server", "pool"},
+				),
+			},
+		},
+		{
+			name: "lease series",
+			opts: []Option{WithSeriesLimit(10)},
+			want: []*prometheus.Desc{
+				metrics.BuildMetricDescription(prefix, "count", "number of dhcp leases",
+					[]string{"name", "address", "server", "status"},
+				),
+				metrics.BuildMetricDescription(prefix, "type_count", "number of static and dynamic dhcp leases",
+					[]string{"name", "address", "server", "type"},
+				),
+				metrics.BuildMetricDescription("dhcp_server", "pool_size", "number of addresses in dhcp server address pool",
+					[]string{"name", "address", "server", "pool"},
+				),
+				metrics.BuildMetricDescription("dhcp_server", "utilization_ratio", "ratio of bound dhcp leases within the address pool to its size (0-1)",
+					[]string{"name", "address", "server", "pool"},
+				),
+				metrics.BuildMetricDescription(prefix, "expires_after", "dhcp lease expires after seconds",
+					[]string{"name", "address", "active_mac_address", "server", "status", "active_address", "hostname"},
+				),
+				metrics.SeriesTruncatedMetricDescription,
+			},
+		},
+	}
 
-	ch := make(chan *prometheus.Desc)
-	done := make(chan struct{})
-	var got []*prometheus.Desc
-	go func() {
-		defer close(done)
-		for desc := range ch {
-			got = append(got, desc)
-		}
-	}()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCollector(tc.opts...)
 
-	c.Describe(ch)
-	close(ch)
+			ch := make(chan *prometheus.Desc)
+			done := make(chan struct{})
+			var got []*prometheus.Desc
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
 
-	<-done
-	r.ElementsMatch([]*prometheus.Desc{
-		metrics.BuildMetricDescription(prefix, "expires_after", "dhcp lease expires after seconds",
-			[]string{"name", "address", "active_mac_address", "server", "status", "active_address", "hostname"},
-		),
-	}, got)
+			c.Describe(ch)
+			close(ch)
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
 }
 
 func Test_dhcpLeaseCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
-	c := NewCollector()
+	countDesc := metrics.BuildMetricDescription(prefix, "count", "number of dhcp leases",
+		[]string{"name", "address", "server", "status"},
+	)
+	typeCountDesc := metrics.BuildMetricDescription(prefix, "type_count", "number of static and dynamic dhcp leases",
+		[]string{"name", "address", "server", "type"},
+	)
+	expiresAfterDesc := metrics.BuildMetricDescription(prefix, "expires_after", "dhcp lease expires after seconds",
+		[]string{"name", "address", "active_mac_address", "server", "status", "active_address", "hostname"},
+	)
 
 	routerOSClientMock := mocks.NewClientMock(t)
 	resetMocks := func() {
 		routerOSClientMock = mocks.NewClientMock(t)
 	}
 
+	leaseSentence := []string{
+		"/ip/dhcp-server/lease/print",
+		"=.proplist=active-mac-address,server,status,expires-after,active-address,host-name,dynamic",
+	}
+	serverSentence := []string{
+		"/ip/dhcp-server/print",
+		"=.proplist=name,address-pool",
+	}
+	poolSentence := []string{
+		"/ip/pool/print",
+		"=.proplist=name,ranges",
+	}
+	setServerMocks := func(ranges string) {
+		routerOSClientMock.RunMock.When(serverSentence...).Then(&routeros.Reply{
+			Re: []*proto.Sentence{
+				{Map: map[string]string{"name": "server", "address-pool": "pool1"}},
+				{Map: map[string]string{"name": "static", "address-pool": "static-only"}},
+			},
+		}, nil)
+		routerOSClientMock.RunMock.When(poolSentence...).Then(&routeros.Reply{
+			Re: []*proto.Sentence{
+				{Map: map[string]string{"name": "pool1", "ranges": ranges}},
+			},
+		}, nil)
+	}
+	leases := &routeros.Reply{
+		Re: []*proto.Sentence{
+			{
+				Map: map[string]string{
+					"active-mac-address": "active-mac-address",
+					"server":             "server",
+					"status":             "bound",
+					"expires-after":      "1m40s",
+					"active-address":     "192.168.1.11",
+					"host-name":          "host-name",
+					"dynamic":            "true",
+				},
+			},
+			{Map: map[string]string{"server": "server", "status": "waiting", "dynamic": "false"}},
+			// static leases only count towards the pool utilization if they are within the pool
+			{Map: map[string]string{"server": "server", "status": "bound", "active-address": "192.168.2.3", "dynamic": "false"}},
+			{Map: map[string]string{"server": "server", "status": "bound", "active-address": "192.168.5.1", "dynamic": "false"}},
+		},
+	}
+	aggregates := []prometheus.Metric{
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 3, "device", "address", "server", "bound"),
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 1, "device", "address", "server", "waiting"),
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 0, "device", "address", "server", "offered"),
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 0, "device", "address", "server", "busy"),
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 0, "device", "address", "static", "bound"),
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 0, "device", "address", "static", "waiting"),
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 0, "device", "address", "static", "offered"),
+		prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, 0, "device", "address", "static", "busy"),
+		prometheus.MustNewConstMetric(typeCountDesc, prometheus.GaugeValue, 1, "device", "address", "server", "dynamic"),
+		prometheus.MustNewConstMetric(typeCountDesc, prometheus.GaugeValue, 3, "device", "address", "server", "static"),
+		prometheus.MustNewConstMetric(typeCountDesc, prometheus.GaugeValue, 0, "device", "address", "static", "dynamic"),
+		prometheus.MustNewConstMetric(typeCountDesc, prometheus.GaugeValue, 0, "device", "address", "static", "static"),
+	}
+	poolMetrics := []prometheus.Metric{
+		prometheus.MustNewConstMetric(poolSizeMetricDescription, prometheus.GaugeValue, 20, "device", "address", "server", "pool1"),
+		prometheus.MustNewConstMetric(utilizationMetricDescription, prometheus.GaugeValue, 0.1, "device", "address", "server", "pool1"),
+	}
+	withMetrics := func(groups ...[]prometheus.Metric) []prometheus.Metric {
+		var res []prometheus.Metric
+		for _, g := range groups {
+			res = append(res, g...)
+		}
+
+		return res
+	}
+
 	testCases := []struct {
 		name     string
+		opts     []Option
 		setMocks func()
 		want     []prometheus.Metric
 		errWant  string
 	}{
 		{
-			name: "success",
+			name: "without lease series",
+			opts: []Option{WithoutLeaseSeries()},
 			setMocks: func() {
-				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
-					r.Equal([]string{
-						"/ip/dhcp-server/lease/print",
-						"=.proplist=active-mac-address,server,status,expires-after,active-address,host-name",
-					}, sentence)
-				}).Return(&routeros.Reply{
-					Re: []*proto.Sentence{
-						{
-							Map: map[string]string{
-								"active-mac-address": "active-mac-address",
-								"server":             "server",
-								"status":             "bound",
-								"expires-after":      "1m40s",
-								"active-address":     "192.168.1.1",
-								"host-name":          "host-name",
-							},
-						},
-					},
-				}, nil)
+				routerOSClientMock.RunMock.When(leaseSentence...).Then(leases, nil)
+				setServerMocks("192.168.1.10-192.168.1.19,192.168.2.0/29,192.168.3.1,192.168.3.2")
 			},
-			want: []prometheus.Metric{
-				prometheus.MustNewConstMetric(
-					metrics.BuildMetricDescription(prefix, "expires_after", "dhcp lease expires after seconds",
-						[]string{"name", "address", "active_mac_address", "server", "status", "active_address", "hostname"},
-					),
-					prometheus.GaugeValue, 100, "device", "address", "active-mac-address", "server",
-					"bound", "192.168.1.1", `"host-name"`,
+			want: withMetrics(aggregates, poolMetrics),
+		},
+		{
+			name: "lease series",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(leaseSentence...).Then(leases, nil)
+				setServerMocks("192.168.1.10-192.168.1.19,192.168.2.0/29,192.168.3.1,192.168.3.2")
+			},
+			want: withMetrics(aggregates, poolMetrics, []prometheus.Metric{
+				prometheus.MustNewConstMetric(expiresAfterDesc, prometheus.GaugeValue, 100, "device", "address", "active-mac-address", "server",
+					"bound", "192.168.1.11", `"host-name"`,
 				),
+			}),
+		},
+		{
+			name: "series limit exceeded",
+			opts: []Option{WithSeriesLimit(1)},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(leaseSentence...).Then(leases, nil)
+				setServerMocks("192.168.1.10-192.168.1.19,192.168.2.0/29,192.168.3.1,192.168.3.2")
 			},
+			want: withMetrics(aggregates, poolMetrics, []prometheus.Metric{
+				prometheus.MustNewConstMetric(metrics.SeriesTruncatedMetricDescription, prometheus.GaugeValue, 1,
+					"device", "address", "dhcp_lease",
+				),
+			}),
+		},
+		{
+			name: "within series limit",
+			opts: []Option{WithSeriesLimit(4)},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(leaseSentence...).Then(leases, nil)
+				setServerMocks("192.168.1.10-192.168.1.19,192.168.2.0/29,192.168.3.1,192.168.3.2")
+			},
+			want: withMetrics(aggregates, poolMetrics, []prometheus.Metric{
+				prometheus.MustNewConstMetric(expiresAfterDesc, prometheus.GaugeValue, 100, "device", "address", "active-mac-address", "server",
+					"bound", "192.168.1.11", `"host-name"`,
+				),
+				prometheus.MustNewConstMetric(metrics.SeriesTruncatedMetricDescription, prometheus.GaugeValue, 0,
+					"device", "address", "dhcp_lease",
+				),
+			}),
 		},
 		{
 			name: "fetch error",
 			setMocks: func() {
-				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
-					r.Equal([]string{
-						"/ip/dhcp-server/lease/print",
-						"=.proplist=active-mac-address,server,status,expires-after,active-address,host-name",
-					}, sentence)
-				}).Return(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When(leaseSentence...).Then(nil, errors.New("some fetch error"))
+				setServerMocks("192.168.1.10-192.168.1.19")
 			},
 			errWant: "failed to fetch dhcp leases: some fetch error",
 		},
 		{
 			name: "parse error",
 			setMocks: func() {
-				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
-					r.Equal([]string{
-						"/ip/dhcp-server/lease/print",
-						"=.proplist=active-mac-address,server,status,expires-after,active-address,host-name",
-					}, sentence)
-				}).Return(&routeros.Reply{
+				routerOSClientMock.RunMock.When(leaseSentence...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
 						{
 							Map: map[string]string{
@@ -127,17 +262,23 @@ func Test_dhcpLeaseCollector_Collect(t *testing.T) {
 								"expires-after":      "a1m20s",
 								"active-address":     "192.168.1.1",
 								"host-name":          "host-name",
+								"dynamic":            "true",
 							},
 						},
+						{Map: map[string]string{"server": "server", "status": "waiting", "dynamic": "false"}},
+						{Map: map[string]string{"server": "server", "status": "bound", "active-address": "192.168.2.3", "dynamic": "false"}},
+						{Map: map[string]string{"server": "server", "status": "bound", "active-address": "192.168.5.1", "dynamic": "false"}},
 					},
 				}, nil)
+				setServerMocks("invalid")
 			},
-			want: []prometheus.Metric{},
+			want: aggregates,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			c := NewCollector(tc.opts...)
 			resetMocks()
 			tc.setMocks()
 			defer routerOSClientMock.MinimockFinish()
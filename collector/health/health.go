@@ -39,17 +39,26 @@ func (c *healthCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
+// Supported - health sensors are only available on RouterBOARD hardware
+func (c *healthCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayBeRouterBoard()
+}
+
 func (c *healthCollector) Collect(ctx *context.Context) error {
 	stats, err := c.fetch(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch system health: %w", err)
 	}
 
+	// RouterOS v6 returns a single entry with a property per sensor, v7 an entry per sensor
+	v6 := ctx.Capabilities.MajorVersion() == 6
 	for _, re := range stats {
-		if metric, ok := re.Map["name"]; ok {
-			c.collectMetricForProperty(metric, re, ctx)
-		} else {
+		metric, ok := re.Map["name"]
+		switch {
+		case v6 || !ok:
 			c.collectForStat(re, ctx)
+		case metricDescriptions[metric] != nil:
+			c.collectMetricForProperty(metric, re, ctx)
 		}
 	}
 
@@ -48,6 +48,16 @@ func Test_healthCollector_Describe(t *testing.T) {
 	}, got)
 }
 
+func Test_healthCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Architecture: "arm64", Board: "RB5009UG+S+"}))
+	r.False(c.Supported(&context.Capabilities{Architecture: "x86_64", Board: "CHR"}))
+}
+
 func Test_healthCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
@@ -59,13 +69,15 @@ func Test_healthCollector_Collect(t *testing.T) {
 	}
 
 	testCases := []struct {
-		name     string
-		setMocks func()
-		want     []prometheus.Metric
-		errWant  string
+		name         string
+		capabilities *context.Capabilities
+		setMocks     func()
+		want         []prometheus.Metric
+		errWant      string
 	}{
 		{
-			name: "success",
+			name:         "success",
+			capabilities: &context.Capabilities{Version: "6.49.10 (long-term)"},
 			setMocks: func() {
 				routerOSClientMock.RunMock.When([]string{"/system/health/print"}...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
@@ -95,7 +107,8 @@ func Test_healthCollector_Collect(t *testing.T) {
 			},
 		},
 		{
-			name: "success_ros7",
+			name:         "success_ros7",
+			capabilities: &context.Capabilities{Version: "7.12.1 (stable)"},
 			setMocks: func() {
 				routerOSClientMock.RunMock.When([]string{"/system/health/print"}...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
@@ -105,6 +118,12 @@ func Test_healthCollector_Collect(t *testing.T) {
 								"value": "40",
 							},
 						},
+						{
+							Map: map[string]string{
+								"name":  "fan1-speed",
+								"value": "4000",
+							},
+						},
 					},
 				}, nil)
 			},
@@ -173,6 +192,7 @@ func Test_healthCollector_Collect(t *testing.T) {
 				MetricsChan:    ch,
 				DeviceName:     "device",
 				DeviceAddress:  "address",
+				Capabilities:   tc.capabilities,
 			})
 			close(ch)
 			if len(tc.errWant) != 0 {
@@ -0,0 +1,306 @@
+package hotspot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/parsers"
+)
+
+var (
+	activeProperties = []string{"server", "user", "address", "mac-address", "uptime", "idle-time", "session-time-left", "bytes-in", "bytes-out"}
+	hostProperties   = []string{"server", "authorized"}
+	serverLabelNames = []string{"name", "address", "server"}
+	// userLabelProperties - maps allowed per-user label names to hotspot active properties
+	userLabelProperties = map[string]string{
+		"server":       "server",
+		"user":         "user",
+		"user_address": "address",
+		"mac_address":  "mac-address",
+	}
+	// sessionLabel - user label identifying a single session, as a user may be logged in from several hosts
+	sessionLabel = "mac_address"
+
+	activeUsersMetricDescription     = metrics.BuildMetricDescription(prefix, "active_users", "number of active hotspot users per server", serverLabelNames)
+	hostsMetricDescription           = metrics.BuildMetricDescription(prefix, "hosts", "number of hotspot hosts per server", serverLabelNames)
+	authorizedHostsMetricDescription = metrics.BuildMetricDescription(prefix, "authorized_hosts", "number of authorized hotspot hosts per server", serverLabelNames)
+)
+
+const prefix = "hotspot"
+
+type hotspotCollector struct {
+	userLabels                    []string
+	userMetricDescriptions        map[string]*metrics.MetricDescription
+	userSessionsMetricDescription *prometheus.Desc
+}
+
+// Option - represents a function on hotspot collector instance
+type Option func(*hotspotCollector)
+
+// WithUserLabels - enables per-user metrics with the given labels, sessions sharing the same label values are summed up
+func WithUserLabels(labels ...string) Option {
+	return func(c *hotspotCollector) {
+		for _, l := range labels {
+			if _, ok := userLabelProperties[l]; !ok {
+				log.WithFields(log.Fields{
+					"collector": prefix,
+					"label":     l,
+				}).Warn("ignoring unknown hotspot user label")
+				continue
+			}
+
+			if containsLabel(c.userLabels, l) {
+				log.WithFields(log.Fields{
+					"collector": prefix,
+					"label":     l,
+				}).Warn("ignoring duplicate hotspot user label")
+				continue
+			}
+
+			c.userLabels = append(c.userLabels, l)
+		}
+	}
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewCollector(opts ...Option) *hotspotCollector {
+	c := &hotspotCollector{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	if len(c.userLabels) != 0 {
+		labelNames := append([]string{"name", "address"}, c.userLabels...)
+		if containsLabel(c.userLabels, sessionLabel) {
+			c.userMetricDescriptions = buildSessionMetricDescriptions(labelNames)
+		} else {
+			c.userMetricDescriptions = buildAggregatedMetricDescriptions(labelNames)
+			c.userSessionsMetricDescription = metrics.BuildMetricDescription(prefix, "user_sessions", "number of active hotspot sessions", labelNames)
+		}
+	}
+
+	return c
+}
+
+// buildSessionMetricDescriptions - builds the metrics of a single session, whose uptime and bytes grow monotonically
+func buildSessionMetricDescriptions(labelNames []string) map[string]*metrics.MetricDescription {
+	return map[string]*metrics.MetricDescription{
+		"uptime": {
+			Desc:      metrics.BuildMetricDescription(prefix, "user_uptime", "hotspot user uptime in seconds", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"idle-time": {
+			Desc:      metrics.BuildMetricDescription(prefix, "user_idle_time", "hotspot user idle time in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"session-time-left": {
+			Desc:      metrics.BuildMetricDescription(prefix, "user_session_time_left", "hotspot user session time left in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"bytes-in": {
+			Desc:      metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"bytes-out": {
+			Desc:      metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+	}
+}
+
+// buildAggregatedMetricDescriptions - builds the metrics summed up over several sessions, durations aren't summed up
+// and the sums drop whenever a session ends, so they are gauges
+func buildAggregatedMetricDescriptions(labelNames []string) map[string]*metrics.MetricDescription {
+	return map[string]*metrics.MetricDescription{
+		"bytes-in": {
+			Desc:      metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"bytes-out": {
+			Desc:      metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+	}
+}
+
+func (c *hotspotCollector) Name() string {
+	return prefix
+}
+
+func (c *hotspotCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeUsersMetricDescription
+	ch <- hostsMetricDescription
+	ch <- authorizedHostsMetricDescription
+	for _, d := range c.userMetricDescriptions {
+		ch <- d.Desc
+	}
+	if c.userSessionsMetricDescription != nil {
+		ch <- c.userSessionsMetricDescription
+	}
+}
+
+func (c *hotspotCollector) Collect(ctx *context.Context) error {
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		stats, err := c.fetch("/ip/hotspot/active/print", activeProperties, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch hotspot active users: %w", err)
+		}
+
+		c.collectActiveUsers(stats, ctx)
+		return nil
+	})
+
+	eg.Go(func() error {
+		stats, err := c.fetch("/ip/hotspot/host/print", hostProperties, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch hotspot hosts: %w", err)
+		}
+
+		c.collectHosts(stats, ctx)
+		return nil
+	})
+
+	return eg.Wait()
+}
+
+func (c *hotspotCollector) fetch(command string, properties []string, ctx *context.Context) ([]*proto.Sentence, error) {
+	reply, err := ctx.RouterOSClient.Run(
+		command,
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.Re, nil
+}
+
+func (c *hotspotCollector) collectActiveUsers(stats []*proto.Sentence, ctx *context.Context) {
+	activeUsers := make(map[string]float64)
+	for _, re := range stats {
+		activeUsers[re.Map["server"]]++
+	}
+
+	for server, v := range activeUsers {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(activeUsersMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, server,
+		)
+	}
+
+	if len(c.userLabels) != 0 {
+		c.collectUsers(stats, ctx)
+	}
+}
+
+type userSeries struct {
+	labelValues []string
+	sessions    float64
+	values      map[string]float64
+}
+
+func (c *hotspotCollector) collectUsers(stats []*proto.Sentence, ctx *context.Context) {
+	series := make(map[string]*userSeries)
+	for _, re := range stats {
+		labelValues := make([]string, 0, len(c.userLabels))
+		for _, l := range c.userLabels {
+			labelValues = append(labelValues, re.Map[userLabelProperties[l]])
+		}
+
+		key := strings.Join(labelValues, "\x00")
+		s, ok := series[key]
+		if !ok {
+			s = &userSeries{labelValues: labelValues, values: make(map[string]float64)}
+			series[key] = s
+		}
+
+		s.sessions++
+		for p := range c.userMetricDescriptions {
+			c.addUserMetricValue(p, re, s, ctx)
+		}
+	}
+
+	for _, s := range series {
+		if c.userSessionsMetricDescription != nil {
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(c.userSessionsMetricDescription, prometheus.GaugeValue, s.sessions,
+				append([]string{ctx.DeviceName, ctx.DeviceAddress}, s.labelValues...)...,
+			)
+		}
+
+		for p, v := range s.values {
+			desc := c.userMetricDescriptions[p]
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, v,
+				append([]string{ctx.DeviceName, ctx.DeviceAddress}, s.labelValues...)...,
+			)
+		}
+	}
+}
+
+func (c *hotspotCollector) addUserMetricValue(property string, re *proto.Sentence, s *userSeries, ctx *context.Context) {
+	value := re.Map[property]
+	if len(value) == 0 {
+		return
+	}
+
+	var (
+		v   float64
+		err error
+	)
+	switch property {
+	case "uptime", "idle-time", "session-time-left":
+		v, err = parsers.ParseDuration(value)
+	default:
+		v, err = strconv.ParseFloat(value, 64)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector": c.Name(),
+			"device":    ctx.DeviceName,
+			"user":      re.Map["user"],
+			"property":  property,
+			"value":     value,
+			"error":     err,
+		}).Error("failed to parse hotspot user metric value")
+		return
+	}
+
+	s.values[property] += v
+}
+
+func (c *hotspotCollector) collectHosts(stats []*proto.Sentence, ctx *context.Context) {
+	hosts := make(map[string]float64)
+	authorizedHosts := make(map[string]float64)
+	for _, re := range stats {
+		server := re.Map["server"]
+		hosts[server]++
+		if re.Map["authorized"] == "true" {
+			authorizedHosts[server]++
+		}
+	}
+
+	for server, v := range hosts {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(hostsMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, server,
+		)
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(authorizedHostsMetricDescription, prometheus.GaugeValue, authorizedHosts[server],
+			ctx.DeviceName, ctx.DeviceAddress, server,
+		)
+	}
+}
@@ -0,0 +1,380 @@
+package hotspot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_hotspotCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("hotspot", c.Name())
+}
+
+func Test_hotspotCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector(WithUserLabels("user", "unknown", "user", "name", "address"))
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	userLabelNames := []string{"name", "address", "user"}
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "active_users", "number of active hotspot users per server", serverLabelNames),
+		metrics.BuildMetricDescription(prefix, "hosts", "number of hotspot hosts per server", serverLabelNames),
+		metrics.BuildMetricDescription(prefix, "authorized_hosts", "number of authorized hotspot hosts per server", serverLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_sessions", "number of active hotspot sessions", userLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", userLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", userLabelNames),
+	}, got)
+}
+
+func Test_hotspotCollector_DescribeSessions(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector(WithUserLabels("user", "mac_address"))
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	userLabelNames := []string{"name", "address", "user", "mac_address"}
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "active_users", "number of active hotspot users per server", serverLabelNames),
+		metrics.BuildMetricDescription(prefix, "hosts", "number of hotspot hosts per server", serverLabelNames),
+		metrics.BuildMetricDescription(prefix, "authorized_hosts", "number of authorized hotspot hosts per server", serverLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_uptime", "hotspot user uptime in seconds", userLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_idle_time", "hotspot user idle time in seconds", userLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_session_time_left", "hotspot user session time left in seconds", userLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", userLabelNames),
+		metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", userLabelNames),
+	}, got)
+}
+
+func Test_hotspotCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	activeReply := &routeros.Reply{
+		Re: []*proto.Sentence{
+			{
+				Map: map[string]string{
+					"server":            "guest",
+					"user":              "user1",
+					"address":           "10.0.0.2",
+					"mac-address":       "00:00:00:00:00:01",
+					"uptime":            "1m",
+					"idle-time":         "10s",
+					"session-time-left": "59m",
+					"bytes-in":          "100",
+					"bytes-out":         "1000",
+				},
+			},
+			{
+				Map: map[string]string{
+					"server":            "guest",
+					"user":              "user1",
+					"address":           "10.0.0.4",
+					"mac-address":       "00:00:00:00:00:03",
+					"uptime":            "30s",
+					"idle-time":         "0s",
+					"session-time-left": "59m30s",
+					"bytes-in":          "50",
+					"bytes-out":         "500",
+				},
+			},
+			{
+				Map: map[string]string{
+					"server":      "guest",
+					"user":        "user2",
+					"address":     "10.0.0.3",
+					"mac-address": "00:00:00:00:00:02",
+					"uptime":      "2m",
+					"idle-time":   "a5s",
+					"bytes-in":    "200",
+					"bytes-out":   "2000",
+				},
+			},
+		},
+	}
+	hostReply := &routeros.Reply{
+		Re: []*proto.Sentence{
+			{Map: map[string]string{"server": "guest", "authorized": "true"}},
+			{Map: map[string]string{"server": "guest", "authorized": "true"}},
+			{Map: map[string]string{"server": "guest", "authorized": "false"}},
+			{Map: map[string]string{"server": "staff", "authorized": "false"}},
+		},
+	}
+
+	aggregatedMetrics := []prometheus.Metric{
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "active_users", "number of active hotspot users per server", serverLabelNames),
+			prometheus.GaugeValue, 3, "device", "address", "guest",
+		),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "hosts", "number of hotspot hosts per server", serverLabelNames),
+			prometheus.GaugeValue, 3, "device", "address", "guest",
+		),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "authorized_hosts", "number of authorized hotspot hosts per server", serverLabelNames),
+			prometheus.GaugeValue, 2, "device", "address", "guest",
+		),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "hosts", "number of hotspot hosts per server", serverLabelNames),
+			prometheus.GaugeValue, 1, "device", "address", "staff",
+		),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "authorized_hosts", "number of authorized hotspot hosts per server", serverLabelNames),
+			prometheus.GaugeValue, 0, "device", "address", "staff",
+		),
+	}
+	serverUserLabelNames := []string{"name", "address", "server"}
+	userLabelNames := []string{"name", "address", "user"}
+	sessionLabelNames := []string{"name", "address", "user", "mac_address"}
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success aggregated",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/active/print",
+					"=.proplist=server,user,address,mac-address,uptime,idle-time,session-time-left,bytes-in,bytes-out",
+				}...).Then(activeReply, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/host/print",
+					"=.proplist=server,authorized",
+				}...).Then(hostReply, nil)
+			},
+			want: aggregatedMetrics,
+		},
+		{
+			name: "success per server",
+			opts: []Option{WithUserLabels("server")},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/active/print",
+					"=.proplist=server,user,address,mac-address,uptime,idle-time,session-time-left,bytes-in,bytes-out",
+				}...).Then(activeReply, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/host/print",
+					"=.proplist=server,authorized",
+				}...).Then(hostReply, nil)
+			},
+			want: append([]prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_sessions", "number of active hotspot sessions", serverUserLabelNames),
+					prometheus.GaugeValue, 3, "device", "address", "guest",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", serverUserLabelNames),
+					prometheus.GaugeValue, 350, "device", "address", "guest",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", serverUserLabelNames),
+					prometheus.GaugeValue, 3500, "device", "address", "guest",
+				),
+			}, aggregatedMetrics...),
+		},
+		{
+			name: "success per user",
+			opts: []Option{WithUserLabels("user")},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/active/print",
+					"=.proplist=server,user,address,mac-address,uptime,idle-time,session-time-left,bytes-in,bytes-out",
+				}...).Then(activeReply, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/host/print",
+					"=.proplist=server,authorized",
+				}...).Then(hostReply, nil)
+			},
+			want: append([]prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_sessions", "number of active hotspot sessions", userLabelNames),
+					prometheus.GaugeValue, 2, "device", "address", "user1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", userLabelNames),
+					prometheus.GaugeValue, 150, "device", "address", "user1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", userLabelNames),
+					prometheus.GaugeValue, 1500, "device", "address", "user1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_sessions", "number of active hotspot sessions", userLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "user2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", userLabelNames),
+					prometheus.GaugeValue, 200, "device", "address", "user2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", userLabelNames),
+					prometheus.GaugeValue, 2000, "device", "address", "user2",
+				),
+			}, aggregatedMetrics...),
+		},
+		{
+			name: "success per session",
+			opts: []Option{WithUserLabels("user", "mac_address")},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/active/print",
+					"=.proplist=server,user,address,mac-address,uptime,idle-time,session-time-left,bytes-in,bytes-out",
+				}...).Then(activeReply, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/host/print",
+					"=.proplist=server,authorized",
+				}...).Then(hostReply, nil)
+			},
+			want: append([]prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_uptime", "hotspot user uptime in seconds", sessionLabelNames),
+					prometheus.CounterValue, 60, "device", "address", "user1", "00:00:00:00:00:01",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_idle_time", "hotspot user idle time in seconds", sessionLabelNames),
+					prometheus.GaugeValue, 10, "device", "address", "user1", "00:00:00:00:00:01",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_session_time_left", "hotspot user session time left in seconds", sessionLabelNames),
+					prometheus.GaugeValue, 3540, "device", "address", "user1", "00:00:00:00:00:01",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", sessionLabelNames),
+					prometheus.CounterValue, 100, "device", "address", "user1", "00:00:00:00:00:01",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", sessionLabelNames),
+					prometheus.CounterValue, 1000, "device", "address", "user1", "00:00:00:00:00:01",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_uptime", "hotspot user uptime in seconds", sessionLabelNames),
+					prometheus.CounterValue, 30, "device", "address", "user1", "00:00:00:00:00:03",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_idle_time", "hotspot user idle time in seconds", sessionLabelNames),
+					prometheus.GaugeValue, 0, "device", "address", "user1", "00:00:00:00:00:03",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_session_time_left", "hotspot user session time left in seconds", sessionLabelNames),
+					prometheus.GaugeValue, 3570, "device", "address", "user1", "00:00:00:00:00:03",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", sessionLabelNames),
+					prometheus.CounterValue, 50, "device", "address", "user1", "00:00:00:00:00:03",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", sessionLabelNames),
+					prometheus.CounterValue, 500, "device", "address", "user1", "00:00:00:00:00:03",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_uptime", "hotspot user uptime in seconds", sessionLabelNames),
+					prometheus.CounterValue, 120, "device", "address", "user2", "00:00:00:00:00:02",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_in", "number of bytes received from hotspot user", sessionLabelNames),
+					prometheus.CounterValue, 200, "device", "address", "user2", "00:00:00:00:00:02",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "user_bytes_out", "number of bytes sent to hotspot user", sessionLabelNames),
+					prometheus.CounterValue, 2000, "device", "address", "user2", "00:00:00:00:00:02",
+				),
+			}, aggregatedMetrics...),
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/active/print",
+					"=.proplist=server,user,address,mac-address,uptime,idle-time,session-time-left,bytes-in,bytes-out",
+				}...).Then(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/hotspot/host/print",
+					"=.proplist=server,authorized",
+				}...).Then(&routeros.Reply{}, nil)
+			},
+			errWant: "failed to fetch hotspot active users: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			c := NewCollector(tc.opts...)
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
@@ -2,12 +2,16 @@ package ethernet
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/routeros.v2"
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
 )
 
@@ -19,14 +23,62 @@ var (
 		"rate":        metrics.BuildMetricDescription(prefix, "rate", "ethernet interface link rate in mbps", labelNames),
 		"full-duplex": metrics.BuildMetricDescription(prefix, "full_duplex", "ethernet interface full duplex status (full duplex = 1)", labelNames),
 	}
+	statsProperties         = []string{"rx-fcs-error", "rx-align-error", "rx-fragment", "rx-overflow", "rx-too-short", "rx-too-long", "rx-jabber", "rx-pause", "rx-code-error", "rx-carrier-error", "rx-length-error", "tx-collision", "tx-late-collision", "tx-excessive-collision", "tx-deferred", "tx-pause", "tx-underrun"}
+	statsMetricDescriptions = map[string]*prometheus.Desc{
+		"rx-fcs-error":           metrics.BuildMetricDescription(prefix, "rx_fcs_error", "number of rx frames with fcs errors on ethernet interface", labelNames),
+		"rx-align-error":         metrics.BuildMetricDescription(prefix, "rx_align_error", "number of rx frames with alignment errors on ethernet interface", labelNames),
+		"rx-fragment":            metrics.BuildMetricDescription(prefix, "rx_fragment", "number of rx fragmented frames on ethernet interface", labelNames),
+		"rx-overflow":            metrics.BuildMetricDescription(prefix, "rx_overflow", "number of rx frames dropped due to buffer overflow on ethernet interface", labelNames),
+		"rx-too-short":           metrics.BuildMetricDescription(prefix, "rx_too_short", "number of rx frames shorter than minimum size on ethernet interface", labelNames),
+		"rx-too-long":            metrics.BuildMetricDescription(prefix, "rx_too_long", "number of rx frames longer than maximum size on ethernet interface", labelNames),
+		"rx-jabber":              metrics.BuildMetricDescription(prefix, "rx_jabber", "number of rx jabber frames on ethernet interface", labelNames),
+		"rx-pause":               metrics.BuildMetricDescription(prefix, "rx_pause", "number of rx pause frames on ethernet interface", labelNames),
+		"rx-code-error":          metrics.BuildMetricDescription(prefix, "rx_code_error", "number of rx frames with code errors on ethernet interface", labelNames),
+		"rx-carrier-error":       metrics.BuildMetricDescription(prefix, "rx_carrier_error", "number of rx frames with carrier errors on ethernet interface", labelNames),
+		"rx-length-error":        metrics.BuildMetricDescription(prefix, "rx_length_error", "number of rx frames with length errors on ethernet interface", labelNames),
+		"tx-collision":           metrics.BuildMetricDescription(prefix, "tx_collision", "number of tx collisions on ethernet interface", labelNames),
+		"tx-late-collision":      metrics.BuildMetricDescription(prefix, "tx_late_collision", "number of tx late collisions on ethernet interface", labelNames),
+		"tx-excessive-collision": metrics.BuildMetricDescription(prefix, "tx_excessive_collision", "number of tx frames aborted due to excessive collisions on ethernet interface", labelNames),
+		"tx-deferred":            metrics.BuildMetricDescription(prefix, "tx_deferred", "number of deferred tx frames on ethernet interface", labelNames),
+		"tx-pause":               metrics.BuildMetricDescription(prefix, "tx_pause", "number of tx pause frames on ethernet interface", labelNames),
+		"tx-underrun":            metrics.BuildMetricDescription(prefix, "tx_underrun", "number of tx underruns on ethernet interface", labelNames),
+	}
+)
+
+const (
+	prefix        = "ethernet"
+	interfaceType = "ether"
 )
 
-const prefix = "ethernet"
+type ethernetCollector struct {
+	stats  bool
+	filter *filter.Filter
+}
+
+// Option - represents a function on ethernet collector instance
+type Option func(*ethernetCollector)
+
+// WithStats - enables collection of ethernet error and pause frame counters
+func WithStats() Option {
+	return func(c *ethernetCollector) {
+		c.stats = true
+	}
+}
+
+// WithFilter - limits metrics to the interfaces matching the filter
+func WithFilter(f *filter.Filter) Option {
+	return func(c *ethernetCollector) {
+		c.filter = f
+	}
+}
 
-type ethernetCollector struct{}
+func NewCollector(opts ...Option) *ethernetCollector {
+	c := &ethernetCollector{}
+	for _, o := range opts {
+		o(c)
+	}
 
-func NewCollector() *ethernetCollector {
-	return &ethernetCollector{}
+	return c
 }
 
 func (c *ethernetCollector) Name() string {
@@ -37,25 +89,54 @@ func (c *ethernetCollector) Describe(ch chan<- *prometheus.Desc) {
 	for _, d := range metricDescriptions {
 		ch <- d
 	}
+
+	if c.stats {
+		for _, d := range statsMetricDescriptions {
+			ch <- d
+		}
+	}
 }
 
 func (c *ethernetCollector) Collect(ctx *context.Context) error {
-	reply, err := ctx.RouterOSClient.Run(
-		"/interface/ethernet/print",
-		"=.proplist=name",
-	)
+	reply, err := c.fetch(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch ethernet interface names: %w", err)
 	}
 
 	names := make([]string, 0, len(reply.Re))
 	for _, re := range reply.Re {
+		if !c.filter.Match(re, interfaceType) {
+			continue
+		}
+
 		names = append(names, re.Map["name"])
+		if c.stats {
+			c.collectStatsForInterface(re, ctx)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
 	}
 
 	return c.collectForInterfaces(names, ctx)
 }
 
+func (c *ethernetCollector) fetch(ctx *context.Context) (*routeros.Reply, error) {
+	if !c.stats {
+		return ctx.RouterOSClient.Run(
+			"/interface/ethernet/print",
+			"=.proplist="+strings.Join(c.filter.AppendProperties([]string{"name"}), ","),
+		)
+	}
+
+	return ctx.RouterOSClient.Run(
+		"/interface/ethernet/print",
+		"=stats=",
+		"=.proplist="+strings.Join(c.filter.AppendProperties(append([]string{"name"}, statsProperties...)), ","),
+	)
+}
+
 func (c *ethernetCollector) collectForInterfaces(interfaces []string, ctx *context.Context) error {
 	reply, err := ctx.RouterOSClient.Run(
 		"/interface/ethernet/monitor",
@@ -109,3 +190,29 @@ func (c *ethernetCollector) collectMetricsForInterface(re *proto.Sentence, ctx *
 		)
 	}
 }
+
+func (c *ethernetCollector) collectStatsForInterface(re *proto.Sentence, ctx *context.Context) {
+	for property := range statsMetricDescriptions {
+		value := re.Map[property]
+		if len(value) == 0 {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"interface": re.Map["name"],
+				"property":  property,
+				"value":     value,
+				"error":     err,
+			}).Error("failed to parse ethernet stats metric value")
+			continue
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(statsMetricDescriptions[property], prometheus.CounterValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, re.Map["name"],
+		)
+	}
+}
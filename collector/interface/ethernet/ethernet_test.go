@@ -2,6 +2,7 @@ package ethernet
 
 import (
 	"errors"
+	"regexp"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -10,6 +11,7 @@ import (
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
 	"github.com/psolru/mikrotik-exporter/routeros/mocks"
 )
@@ -51,8 +53,6 @@ func Test_ethernetCollector_Describe(t *testing.T) {
 func Test_ethernetCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
-	c := NewCollector()
-
 	routerOSClientMock := mocks.NewClientMock(t)
 	resetMocks := func() {
 		routerOSClientMock = mocks.NewClientMock(t)
@@ -60,6 +60,7 @@ func Test_ethernetCollector_Collect(t *testing.T) {
 
 	testCases := []struct {
 		name     string
+		opts     []Option
 		setMocks func()
 		want     []prometheus.Metric
 		errWant  string
@@ -113,6 +114,116 @@ func Test_ethernetCollector_Collect(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "success with stats",
+			opts: []Option{WithStats()},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/print",
+					"=stats=",
+					"=.proplist=name,rx-fcs-error,rx-align-error,rx-fragment,rx-overflow,rx-too-short,rx-too-long,rx-jabber,rx-pause,rx-code-error,rx-carrier-error,rx-length-error,tx-collision,tx-late-collision,tx-excessive-collision,tx-deferred,tx-pause,tx-underrun",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":         "ether1",
+								"rx-fcs-error": "12",
+								"tx-pause":     "3",
+								"rx-too-long":  "a1",
+							},
+						},
+					},
+				}, nil)
+
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/monitor",
+					"=numbers=ether1",
+					"=once=",
+					"=.proplist=name,status,rate,full-duplex",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":   "ether1",
+								"status": "no-link",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "status", "ethernet interface status (up = 1)", labelNames),
+					prometheus.GaugeValue, 0, "device", "address", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "rx_fcs_error", "number of rx frames with fcs errors on ethernet interface", labelNames),
+					prometheus.CounterValue, 12, "device", "address", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "tx_pause", "number of tx pause frames on ethernet interface", labelNames),
+					prometheus.CounterValue, 3, "device", "address", "ether1",
+				),
+			},
+		},
+		{
+			name: "success with filter",
+			opts: []Option{WithFilter(&filter.Filter{
+				ExcludeComment: regexp.MustCompile("unused"),
+				SkipDisabled:   true,
+			})},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/print",
+					"=.proplist=name,comment,disabled",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":     "ether1",
+								"comment":  "uplink",
+								"disabled": "false",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":     "ether2",
+								"comment":  "unused",
+								"disabled": "false",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":     "ether3",
+								"disabled": "true",
+							},
+						},
+					},
+				}, nil)
+
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/monitor",
+					"=numbers=ether1",
+					"=once=",
+					"=.proplist=name,status,rate,full-duplex",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":   "ether1",
+								"status": "link-ok",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "status", "ethernet interface status (up = 1)", labelNames),
+					prometheus.GaugeValue, 1, "device", "address", "ether1",
+				),
+			},
+		},
 		{
 			name: "fetch error",
 			setMocks: func() {
@@ -133,6 +244,8 @@ func Test_ethernetCollector_Collect(t *testing.T) {
 			tc.setMocks()
 			defer routerOSClientMock.MinimockFinish()
 
+			c := NewCollector(tc.opts...)
+
 			ch := make(chan prometheus.Metric)
 			done := make(chan struct{})
 			var got []prometheus.Metric
@@ -0,0 +1,98 @@
+package filter
+
+import (
+	"regexp"
+
+	"gopkg.in/routeros.v2/proto"
+)
+
+// Filter - represents interface include/exclude rules shared by the interface collectors.
+// A nil Filter matches every interface.
+type Filter struct {
+	// IncludeName - interface name has to match, optional
+	IncludeName *regexp.Regexp
+	// ExcludeName - interface name must not match, optional
+	ExcludeName *regexp.Regexp
+	// IncludeType - interface type has to match, optional
+	IncludeType *regexp.Regexp
+	// ExcludeType - interface type must not match, optional
+	ExcludeType *regexp.Regexp
+	// IncludeComment - interface comment has to match, optional
+	IncludeComment *regexp.Regexp
+	// ExcludeComment - interface comment must not match, optional
+	ExcludeComment *regexp.Regexp
+	// SkipDynamic - skips dynamic interfaces
+	SkipDynamic bool
+	// SkipDisabled - skips disabled interfaces
+	SkipDisabled bool
+}
+
+// AppendProperties - returns a copy of properties extended with the properties needed to evaluate the filter
+func (f *Filter) AppendProperties(properties []string) []string {
+	res := make([]string, len(properties), len(properties)+3)
+	copy(res, properties)
+	if f == nil {
+		return res
+	}
+
+	var needed []string
+	if f.IncludeComment != nil || f.ExcludeComment != nil {
+		needed = append(needed, "comment")
+	}
+	if f.SkipDynamic {
+		needed = append(needed, "dynamic")
+	}
+	if f.SkipDisabled {
+		needed = append(needed, "disabled")
+	}
+
+	for _, p := range needed {
+		if !contains(res, p) {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+// Match - reports whether the interface in the sentence passes the filter.
+// interfaceType is passed separately as menus like /interface/ethernet don't return it.
+func (f *Filter) Match(re *proto.Sentence, interfaceType string) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.SkipDynamic && re.Map["dynamic"] == "true" {
+		return false
+	}
+
+	if f.SkipDisabled && re.Map["disabled"] == "true" {
+		return false
+	}
+
+	return matchRegexps(f.IncludeName, f.ExcludeName, re.Map["name"]) &&
+		matchRegexps(f.IncludeType, f.ExcludeType, interfaceType) &&
+		matchRegexps(f.IncludeComment, f.ExcludeComment, re.Map["comment"])
+}
+
+func matchRegexps(include, exclude *regexp.Regexp, value string) bool {
+	if include != nil && !include.MatchString(value) {
+		return false
+	}
+
+	if exclude != nil && exclude.MatchString(value) {
+		return false
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package filter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2/proto"
+)
+
+func TestFilter_AppendProperties(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		name   string
+		filter *Filter
+		want   []string
+	}{
+		{
+			name: "nil filter",
+			want: []string{"name", "disabled"},
+		},
+		{
+			name: "all properties",
+			filter: &Filter{
+				ExcludeComment: regexp.MustCompile("ignore"),
+				SkipDynamic:    true,
+				SkipDisabled:   true,
+			},
+			want: []string{"name", "disabled", "comment", "dynamic"},
+		},
+		{
+			name: "name only",
+			filter: &Filter{
+				IncludeName: regexp.MustCompile("^ether"),
+			},
+			want: []string{"name", "disabled"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			properties := []string{"name", "disabled"}
+			r.Equal(tc.want, tc.filter.AppendProperties(properties))
+			r.Equal([]string{"name", "disabled"}, properties)
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		name          string
+		filter        *Filter
+		sentence      map[string]string
+		interfaceType string
+		want          bool
+	}{
+		{
+			name:          "nil filter",
+			sentence:      map[string]string{"name": "<pppoe-user>", "dynamic": "true"},
+			interfaceType: "pppoe-in",
+			want:          true,
+		},
+		{
+			name:          "skip dynamic",
+			filter:        &Filter{SkipDynamic: true},
+			sentence:      map[string]string{"name": "<pppoe-user>", "dynamic": "true"},
+			interfaceType: "pppoe-in",
+			want:          false,
+		},
+		{
+			name:          "skip disabled",
+			filter:        &Filter{SkipDisabled: true},
+			sentence:      map[string]string{"name": "ether5", "disabled": "true"},
+			interfaceType: "ether",
+			want:          false,
+		},
+		{
+			name:          "include name match",
+			filter:        &Filter{IncludeName: regexp.MustCompile("^(ether|sfp)")},
+			sentence:      map[string]string{"name": "sfp-sfpplus1"},
+			interfaceType: "ether",
+			want:          true,
+		},
+		{
+			name:          "include name mismatch",
+			filter:        &Filter{IncludeName: regexp.MustCompile("^(ether|sfp)")},
+			sentence:      map[string]string{"name": "bridge"},
+			interfaceType: "bridge",
+			want:          false,
+		},
+		{
+			name:          "exclude type",
+			filter:        &Filter{ExcludeType: regexp.MustCompile("^(pppoe-in|l2tp-in)$")},
+			sentence:      map[string]string{"name": "<l2tp-user>"},
+			interfaceType: "l2tp-in",
+			want:          false,
+		},
+		{
+			name:          "exclude comment",
+			filter:        &Filter{ExcludeComment: regexp.MustCompile("no-monitoring")},
+			sentence:      map[string]string{"name": "ether2", "comment": "lab, no-monitoring"},
+			interfaceType: "ether",
+			want:          false,
+		},
+		{
+			name: "include and exclude",
+			filter: &Filter{
+				IncludeType:    regexp.MustCompile("^ether$"),
+				ExcludeName:    regexp.MustCompile("^ether1$"),
+				IncludeComment: regexp.MustCompile("uplink"),
+				SkipDynamic:    true,
+				SkipDisabled:   true,
+			},
+			sentence:      map[string]string{"name": "ether2", "comment": "uplink", "dynamic": "false", "disabled": "false"},
+			interfaceType: "ether",
+			want:          true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r.Equal(tc.want, tc.filter.Match(&proto.Sentence{Map: tc.sentence}, tc.interfaceType))
+		})
+	}
+}
@@ -10,13 +10,38 @@ import (
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/parsers"
 )
 
 var (
-	properties         = []string{"name", "type", "disabled", "comment", "running", "slave", "actual-mtu", "rx-byte", "tx-byte", "rx-packet", "tx-packet", "rx-error", "tx-error", "rx-drop", "tx-drop", "link-downs"}
-	labelNames         = []string{"name", "address", "interface", "type", "disabled", "comment", "running", "slave"}
-	metricDescriptions = map[string]*metrics.MetricDescription{
+	properties          = []string{"name", "type", "disabled", "comment", "running", "slave", "actual-mtu", "rx-byte", "tx-byte", "rx-packet", "tx-packet", "rx-error", "tx-error", "rx-drop", "tx-drop", "link-downs", "last-link-up-time", "last-link-down-time"}
+	labelNames          = []string{"name", "address", "interface", "type", "disabled", "comment", "running", "slave"}
+	statelessLabelNames = []string{"name", "address", "interface", "type", "comment"}
+	// statusMetricDescriptions - status metrics are always exported without state labels to keep series stable
+	statusMetricDescriptions = map[string]*metrics.MetricDescription{
+		"running": {
+			Desc:      metrics.BuildMetricDescription(prefix, "running", "interface running status (running = 1)", statelessLabelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"disabled": {
+			Desc:      metrics.BuildMetricDescription(prefix, "disabled", "interface disabled status (disabled = 1)", statelessLabelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"last-link-up-time": {
+			Desc:      metrics.BuildMetricDescription(prefix, "last_link_up_time", "last time the interface link went up as unix timestamp", statelessLabelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"last-link-down-time": {
+			Desc:      metrics.BuildMetricDescription(prefix, "last_link_down_time", "last time the interface link went down as unix timestamp", statelessLabelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+	}
+)
+
+func buildMetricDescriptions(labelNames []string) map[string]*metrics.MetricDescription {
+	return map[string]*metrics.MetricDescription{
 		"actual-mtu": {
 			Desc:      metrics.BuildMetricDescription(prefix, "actual_mtu", "actual mtu of interface", labelNames),
 			ValueType: prometheus.GaugeValue,
@@ -58,14 +83,48 @@ var (
 			ValueType: prometheus.CounterValue,
 		},
 	}
-)
+}
 
 const prefix = "interface"
 
-type interfaceCollector struct{}
+type interfaceCollector struct {
+	stateLabels        bool
+	filter             *filter.Filter
+	metricDescriptions map[string]*metrics.MetricDescription
+}
+
+// Option - represents a function on interface collector instance
+type Option func(*interfaceCollector)
+
+// WithoutStateLabels - drops disabled, running and slave labels from interface counters
+func WithoutStateLabels() Option {
+	return func(c *interfaceCollector) {
+		c.stateLabels = false
+	}
+}
+
+// WithFilter - limits metrics to the interfaces matching the filter
+func WithFilter(f *filter.Filter) Option {
+	return func(c *interfaceCollector) {
+		c.filter = f
+	}
+}
+
+func NewCollector(opts ...Option) *interfaceCollector {
+	c := &interfaceCollector{
+		stateLabels: true,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	if c.stateLabels {
+		c.metricDescriptions = buildMetricDescriptions(labelNames)
+	} else {
+		c.metricDescriptions = buildMetricDescriptions(statelessLabelNames)
+	}
 
-func NewCollector() *interfaceCollector {
-	return &interfaceCollector{}
+	return c
 }
 
 func (c *interfaceCollector) Name() string {
@@ -73,7 +132,11 @@ func (c *interfaceCollector) Name() string {
 }
 
 func (c *interfaceCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, d := range metricDescriptions {
+	for _, d := range c.metricDescriptions {
+		ch <- d.Desc
+	}
+
+	for _, d := range statusMetricDescriptions {
 		ch <- d.Desc
 	}
 }
@@ -94,7 +157,7 @@ func (c *interfaceCollector) Collect(ctx *context.Context) error {
 func (c *interfaceCollector) fetch(ctx *context.Context) ([]*proto.Sentence, error) {
 	reply, err := ctx.RouterOSClient.Run(
 		"/interface/print",
-		"=.proplist="+strings.Join(properties, ","),
+		"=.proplist="+strings.Join(c.filter.AppendProperties(properties), ","),
 	)
 	if err != nil {
 		return nil, err
@@ -104,9 +167,17 @@ func (c *interfaceCollector) fetch(ctx *context.Context) ([]*proto.Sentence, err
 }
 
 func (c *interfaceCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
-	for p := range metricDescriptions {
+	if !c.filter.Match(re, re.Map["type"]) {
+		return
+	}
+
+	for p := range c.metricDescriptions {
 		c.collectMetricForProperty(p, re, ctx)
 	}
+
+	for p := range statusMetricDescriptions {
+		c.collectStatusMetricForProperty(p, re, ctx)
+	}
 }
 
 func (c *interfaceCollector) collectMetricForProperty(property string, re *proto.Sentence, ctx *context.Context) {
@@ -128,8 +199,50 @@ func (c *interfaceCollector) collectMetricForProperty(property string, re *proto
 		return
 	}
 
-	metric := metricDescriptions[property]
+	metric := c.metricDescriptions[property]
+	if !c.stateLabels {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(metric.Desc, metric.ValueType, v,
+			ctx.DeviceName, ctx.DeviceAddress,
+			re.Map["name"], re.Map["type"], re.Map["comment"])
+		return
+	}
+
 	ctx.MetricsChan <- prometheus.MustNewConstMetric(metric.Desc, metric.ValueType, v,
 		ctx.DeviceName, ctx.DeviceAddress,
 		re.Map["name"], re.Map["type"], re.Map["disabled"], re.Map["comment"], re.Map["running"], re.Map["slave"])
 }
+
+func (c *interfaceCollector) collectStatusMetricForProperty(property string, re *proto.Sentence, ctx *context.Context) {
+	value := re.Map[property]
+	if len(value) == 0 {
+		return
+	}
+
+	var v float64
+	switch property {
+	case "last-link-up-time", "last-link-down-time":
+		t, err := parsers.ParseDatetimeInLocation(value, ctx.Capabilities.Location())
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"interface": re.Map["name"],
+				"property":  property,
+				"value":     value,
+				"error":     err,
+			}).Error("failed to parse interface metric value")
+			return
+		}
+
+		v = float64(t.Unix())
+	default:
+		if value == "true" {
+			v = 1
+		}
+	}
+
+	metric := statusMetricDescriptions[property]
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(metric.Desc, metric.ValueType, v,
+		ctx.DeviceName, ctx.DeviceAddress,
+		re.Map["name"], re.Map["type"], re.Map["comment"])
+}
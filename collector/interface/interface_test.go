@@ -2,7 +2,9 @@ package _interface
 
 import (
 	"errors"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
@@ -10,6 +12,7 @@ import (
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
 	"github.com/psolru/mikrotik-exporter/routeros/mocks"
 )
@@ -52,13 +55,18 @@ func Test_interfaceCollector_Describe(t *testing.T) {
 		metrics.BuildMetricDescription(prefix, "rx_drop", "number of dropped rx packets on interface", labelNames),
 		metrics.BuildMetricDescription(prefix, "tx_drop", "number of dropped tx packets on interface", labelNames),
 		metrics.BuildMetricDescription(prefix, "link_downs", "number of times link has gone down on interface", labelNames),
+		metrics.BuildMetricDescription(prefix, "running", "interface running status (running = 1)", statelessLabelNames),
+		metrics.BuildMetricDescription(prefix, "disabled", "interface disabled status (disabled = 1)", statelessLabelNames),
+		metrics.BuildMetricDescription(prefix, "last_link_up_time", "last time the interface link went up as unix timestamp", statelessLabelNames),
+		metrics.BuildMetricDescription(prefix, "last_link_down_time", "last time the interface link went down as unix timestamp", statelessLabelNames),
 	}, got)
 }
 
 func Test_interfaceCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
-	c := NewCollector()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	r.NoError(err)
 
 	routerOSClientMock := mocks.NewClientMock(t)
 	resetMocks := func() {
@@ -66,37 +74,41 @@ func Test_interfaceCollector_Collect(t *testing.T) {
 	}
 
 	testCases := []struct {
-		name     string
-		setMocks func()
-		want     []prometheus.Metric
-		errWant  string
+		name         string
+		opts         []Option
+		capabilities *context.Capabilities
+		setMocks     func()
+		want         []prometheus.Metric
+		errWant      string
 	}{
 		{
 			name: "success",
 			setMocks: func() {
 				routerOSClientMock.RunMock.When([]string{
 					"/interface/print",
-					"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs",
+					"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs,last-link-up-time,last-link-down-time",
 				}...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
 						{
 							Map: map[string]string{
-								"name":       "ether1",
-								"type":       "ethernet",
-								"disabled":   "false",
-								"comment":    "ether1",
-								"running":    "true",
-								"slave":      "false",
-								"actual-mtu": "1500",
-								"rx-byte":    "100",
-								"tx-byte":    "10",
-								"rx-packet":  "10",
-								"tx-packet":  "1",
-								"rx-error":   "0",
-								"tx-error":   "0",
-								"rx-drop":    "0",
-								"tx-drop":    "0",
-								"link-downs": "2",
+								"name":                "ether1",
+								"type":                "ethernet",
+								"disabled":            "false",
+								"comment":             "ether1",
+								"running":             "true",
+								"slave":               "false",
+								"actual-mtu":          "1500",
+								"rx-byte":             "100",
+								"tx-byte":             "10",
+								"rx-packet":           "10",
+								"tx-packet":           "1",
+								"rx-error":            "0",
+								"tx-error":            "0",
+								"rx-drop":             "0",
+								"tx-drop":             "0",
+								"link-downs":          "2",
+								"last-link-up-time":   "Oct/05/2019 16:34:15",
+								"last-link-down-time": "2019-10-05 16:34:10",
 							},
 						},
 					},
@@ -143,6 +155,55 @@ func Test_interfaceCollector_Collect(t *testing.T) {
 					metrics.BuildMetricDescription(prefix, "link_downs", "number of times link has gone down on interface", labelNames),
 					prometheus.CounterValue, 2, "device", "address", "ether1", "ethernet", "false", "ether1", "true", "false",
 				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "running", "interface running status (running = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "disabled", "interface disabled status (disabled = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 0, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "last_link_up_time", "last time the interface link went up as unix timestamp", statelessLabelNames),
+					prometheus.GaugeValue, 1570293255, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "last_link_down_time", "last time the interface link went down as unix timestamp", statelessLabelNames),
+					prometheus.GaugeValue, 1570293250, "device", "address", "ether1", "ethernet", "ether1",
+				),
+			},
+		},
+		{
+			name:         "success in device time zone",
+			opts:         []Option{WithoutStateLabels()},
+			capabilities: &context.Capabilities{TimeZone: berlin},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/print",
+					"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs,last-link-up-time,last-link-down-time",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":                "ether1",
+								"type":                "ethernet",
+								"comment":             "ether1",
+								"last-link-up-time":   "Oct/05/2019 16:34:15",
+								"last-link-down-time": "2019-10-05 16:34:10",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "last_link_up_time", "last time the interface link went up as unix timestamp", statelessLabelNames),
+					prometheus.GaugeValue, 1570286055, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "last_link_down_time", "last time the interface link went down as unix timestamp", statelessLabelNames),
+					prometheus.GaugeValue, 1570286050, "device", "address", "ether1", "ethernet", "ether1",
+				),
 			},
 		},
 		{
@@ -151,7 +212,7 @@ func Test_interfaceCollector_Collect(t *testing.T) {
 				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
 					r.Equal([]string{
 						"/interface/print",
-						"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs",
+						"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs,last-link-up-time,last-link-down-time",
 					}, sentence)
 				}).Return(nil, errors.New("some fetch error"))
 			},
@@ -162,7 +223,7 @@ func Test_interfaceCollector_Collect(t *testing.T) {
 			setMocks: func() {
 				routerOSClientMock.RunMock.When([]string{
 					"/interface/print",
-					"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs",
+					"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs,last-link-up-time,last-link-down-time",
 				}...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
 						{
@@ -193,12 +254,127 @@ func Test_interfaceCollector_Collect(t *testing.T) {
 					metrics.BuildMetricDescription(prefix, "actual_mtu", "actual mtu of interface", labelNames),
 					prometheus.GaugeValue, 1500, "device", "address", "ether1", "ethernet", "false", "ether1", "true", "false",
 				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "running", "interface running status (running = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "disabled", "interface disabled status (disabled = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 0, "device", "address", "ether1", "ethernet", "ether1",
+				),
+			},
+		},
+		{
+			name: "success with filter",
+			opts: []Option{
+				WithoutStateLabels(),
+				WithFilter(&filter.Filter{
+					ExcludeType: regexp.MustCompile("^pppoe-in$"),
+					SkipDynamic: true,
+				}),
+			},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/print",
+					"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs,last-link-up-time,last-link-down-time,dynamic",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":       "ether1",
+								"type":       "ether",
+								"running":    "true",
+								"disabled":   "false",
+								"dynamic":    "false",
+								"actual-mtu": "1500",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":       "<pppoe-user1>",
+								"type":       "pppoe-in",
+								"running":    "true",
+								"disabled":   "false",
+								"dynamic":    "true",
+								"actual-mtu": "1480",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":       "pppoe-out1",
+								"type":       "pppoe-out",
+								"running":    "false",
+								"disabled":   "false",
+								"dynamic":    "true",
+								"actual-mtu": "1480",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "actual_mtu", "actual mtu of interface", statelessLabelNames),
+					prometheus.GaugeValue, 1500, "device", "address", "ether1", "ether", "",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "running", "interface running status (running = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "ether1", "ether", "",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "disabled", "interface disabled status (disabled = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 0, "device", "address", "ether1", "ether", "",
+				),
+			},
+		},
+		{
+			name: "success without state labels",
+			opts: []Option{WithoutStateLabels()},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/print",
+					"=.proplist=name,type,disabled,comment,running,slave,actual-mtu,rx-byte,tx-byte,rx-packet,tx-packet,rx-error,tx-error,rx-drop,tx-drop,link-downs,last-link-up-time,last-link-down-time",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":       "ether1",
+								"type":       "ethernet",
+								"disabled":   "false",
+								"comment":    "ether1",
+								"running":    "true",
+								"slave":      "false",
+								"actual-mtu": "1500",
+								"rx-byte":    "100",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "actual_mtu", "actual mtu of interface", statelessLabelNames),
+					prometheus.GaugeValue, 1500, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "rx_byte", "number of rx bytes on interface", statelessLabelNames),
+					prometheus.CounterValue, 100, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "running", "interface running status (running = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 1, "device", "address", "ether1", "ethernet", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "disabled", "interface disabled status (disabled = 1)", statelessLabelNames),
+					prometheus.GaugeValue, 0, "device", "address", "ether1", "ethernet", "ether1",
+				),
 			},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			c := NewCollector(tc.opts...)
 			resetMocks()
 			tc.setMocks()
 			defer routerOSClientMock.MinimockFinish()
@@ -218,6 +394,7 @@ func Test_interfaceCollector_Collect(t *testing.T) {
 				MetricsChan:    ch,
 				DeviceName:     "device",
 				DeviceAddress:  "address",
+				Capabilities:   tc.capabilities,
 			})
 			close(ch)
 			if len(tc.errWant) != 0 {
@@ -10,6 +10,7 @@ import (
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
 )
 
@@ -24,12 +25,32 @@ var (
 	}
 )
 
-const prefix = "lte"
+const (
+	prefix        = "lte"
+	interfaceType = "lte"
+)
+
+type lteCollector struct {
+	filter *filter.Filter
+}
 
-type lteCollector struct{}
+// Option - represents a function on lte collector instance
+type Option func(*lteCollector)
 
-func NewCollector() *lteCollector {
-	return &lteCollector{}
+// WithFilter - limits metrics to the interfaces matching the filter
+func WithFilter(f *filter.Filter) Option {
+	return func(c *lteCollector) {
+		c.filter = f
+	}
+}
+
+func NewCollector(opts ...Option) *lteCollector {
+	c := &lteCollector{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
 }
 
 func (c *lteCollector) Name() string {
@@ -42,11 +63,16 @@ func (c *lteCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
+// Supported - LTE modems are only available on RouterBOARD hardware, RouterOS v6 ships them in the lte package
+func (c *lteCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayBeRouterBoard() && (caps.MajorVersion() != 6 || caps.MayHavePackage("lte"))
+}
+
 func (c *lteCollector) Collect(ctx *context.Context) error {
 	reply, err := ctx.RouterOSClient.Run(
 		"/interface/lte/print",
 		"?disabled=false",
-		"=.proplist=name",
+		"=.proplist="+strings.Join(c.filter.AppendProperties([]string{"name"}), ","),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch lte interface names: %w", err)
@@ -54,7 +80,13 @@ func (c *lteCollector) Collect(ctx *context.Context) error {
 
 	names := make([]string, 0, len(reply.Re))
 	for _, re := range reply.Re {
-		names = append(names, re.Map["name"])
+		if c.filter.Match(re, interfaceType) {
+			names = append(names, re.Map["name"])
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
 	}
 
 	return c.collectForInterfaces(names, ctx)
@@ -49,6 +49,18 @@ func Test_lteCollector_Describe(t *testing.T) {
 	}, got)
 }
 
+func Test_lteCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Version: "7.12.1 (stable)", Architecture: "arm", Packages: []string{"routeros"}}))
+	r.True(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Architecture: "mipsbe", Packages: []string{"system", "lte"}}))
+	r.False(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Architecture: "mipsbe", Packages: []string{"system"}}))
+	r.False(c.Supported(&context.Capabilities{Version: "7.12.1 (stable)", Architecture: "x86_64", Board: "CHR"}))
+}
+
 func Test_lteCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
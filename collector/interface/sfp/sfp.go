@@ -10,6 +10,7 @@ import (
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
 )
 
@@ -27,12 +28,32 @@ var (
 	}
 )
 
-const prefix = "sfp"
+const (
+	prefix        = "sfp"
+	interfaceType = "ether"
+)
+
+type sfpCollector struct {
+	filter *filter.Filter
+}
+
+// Option - represents a function on sfp collector instance
+type Option func(*sfpCollector)
+
+// WithFilter - limits metrics to the interfaces matching the filter
+func WithFilter(f *filter.Filter) Option {
+	return func(c *sfpCollector) {
+		c.filter = f
+	}
+}
 
-type sfpCollector struct{}
+func NewCollector(opts ...Option) *sfpCollector {
+	c := &sfpCollector{}
+	for _, o := range opts {
+		o(c)
+	}
 
-func NewCollector() *sfpCollector {
-	return &sfpCollector{}
+	return c
 }
 
 func (c *sfpCollector) Name() string {
@@ -45,10 +66,15 @@ func (c *sfpCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
+// Supported - SFP cages are only available on RouterBOARD hardware
+func (c *sfpCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayBeRouterBoard()
+}
+
 func (c *sfpCollector) Collect(ctx *context.Context) error {
 	reply, err := ctx.RouterOSClient.Run(
 		"/interface/ethernet/print",
-		"=.proplist=name",
+		"=.proplist="+strings.Join(c.filter.AppendProperties([]string{"name"}), ","),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch sfp interface names: %w", err)
@@ -57,11 +83,15 @@ func (c *sfpCollector) Collect(ctx *context.Context) error {
 	interfaces := make([]string, 0, len(reply.Re))
 	for _, re := range reply.Re {
 		name := re.Map["name"]
-		if strings.HasPrefix(name, "sfp") {
+		if strings.HasPrefix(name, "sfp") && c.filter.Match(re, interfaceType) {
 			interfaces = append(interfaces, name)
 		}
 	}
 
+	if len(interfaces) == 0 {
+		return nil
+	}
+
 	return c.collectForInterfaces(interfaces, ctx)
 }
 
@@ -0,0 +1,170 @@
+package switch_port
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	switchProperties   = []string{"name", "type"}
+	properties         = []string{"name", "switch", "rx-fcs-error", "rx-align-error", "rx-fragment", "rx-overflow", "rx-too-short", "rx-too-long", "rx-pause", "tx-pause", "rx-drop", "tx-drop", "tx-collision", "policy-drop-packet"}
+	labelNames         = []string{"name", "address", "switch", "port"}
+	metricDescriptions = map[string]*metrics.MetricDescription{
+		"rx-fcs-error": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_fcs_error", "number of rx frames with fcs errors on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"rx-align-error": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_align_error", "number of rx frames with alignment errors on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"rx-fragment": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_fragment", "number of rx fragmented frames on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"rx-overflow": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_overflow", "number of rx frames dropped due to buffer overflow on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"rx-too-short": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_too_short", "number of rx frames shorter than minimum size on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"rx-too-long": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_too_long", "number of rx frames longer than maximum size on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"rx-pause": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_pause", "number of rx pause frames on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"tx-pause": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_tx_pause", "number of tx pause frames on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"rx-drop": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_rx_drop", "number of dropped rx frames on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"tx-drop": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_tx_drop", "number of dropped tx frames on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"tx-collision": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_tx_collision", "number of tx collisions on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+		"policy-drop-packet": {
+			Desc:      metrics.BuildMetricDescription(prefix, "port_policy_drop_packet", "number of packets dropped by policer on switch port", labelNames),
+			ValueType: prometheus.CounterValue,
+		},
+	}
+	switchInfoMetricDescription = metrics.BuildMetricDescription(prefix, "info", "switch chip info", []string{"name", "address", "switch", "type"})
+)
+
+const prefix = "switch"
+
+type switchPortCollector struct{}
+
+func NewCollector() *switchPortCollector {
+	return &switchPortCollector{}
+}
+
+func (c *switchPortCollector) Name() string {
+	return prefix
+}
+
+func (c *switchPortCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- switchInfoMetricDescription
+	for _, d := range metricDescriptions {
+		ch <- d.Desc
+	}
+}
+
+func (c *switchPortCollector) Collect(ctx *context.Context) error {
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		return c.collectSwitches(ctx)
+	})
+
+	eg.Go(func() error {
+		return c.collectPorts(ctx)
+	})
+
+	return eg.Wait()
+}
+
+func (c *switchPortCollector) collectSwitches(ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/ethernet/switch/print",
+		"=.proplist="+strings.Join(switchProperties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch switches: %w", err)
+	}
+
+	for _, re := range reply.Re {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(switchInfoMetricDescription, prometheus.GaugeValue, 1,
+			ctx.DeviceName, ctx.DeviceAddress, re.Map["name"], re.Map["type"],
+		)
+	}
+
+	return nil
+}
+
+func (c *switchPortCollector) collectPorts(ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/ethernet/switch/port/print",
+		"=stats=",
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch switch port stats: %w", err)
+	}
+
+	for _, re := range reply.Re {
+		c.collectForStat(re, ctx)
+	}
+
+	return nil
+}
+
+func (c *switchPortCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
+	for p := range metricDescriptions {
+		c.collectMetricForProperty(p, re, ctx)
+	}
+}
+
+func (c *switchPortCollector) collectMetricForProperty(property string, re *proto.Sentence, ctx *context.Context) {
+	value := re.Map[property]
+	if len(value) == 0 {
+		return
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector": c.Name(),
+			"device":    ctx.DeviceName,
+			"port":      re.Map["name"],
+			"property":  property,
+			"value":     value,
+			"error":     err,
+		}).Error("failed to parse switch port metric value")
+		return
+	}
+
+	metric := metricDescriptions[property]
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(metric.Desc, metric.ValueType, v,
+		ctx.DeviceName, ctx.DeviceAddress, re.Map["switch"], re.Map["name"],
+	)
+}
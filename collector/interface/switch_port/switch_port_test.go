@@ -0,0 +1,181 @@
+package switch_port
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_switchPortCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("switch", c.Name())
+}
+
+func Test_switchPortCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "info", "switch chip info", []string{"name", "address", "switch", "type"}),
+		metrics.BuildMetricDescription(prefix, "port_rx_fcs_error", "number of rx frames with fcs errors on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_rx_align_error", "number of rx frames with alignment errors on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_rx_fragment", "number of rx fragmented frames on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_rx_overflow", "number of rx frames dropped due to buffer overflow on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_rx_too_short", "number of rx frames shorter than minimum size on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_rx_too_long", "number of rx frames longer than maximum size on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_rx_pause", "number of rx pause frames on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_tx_pause", "number of tx pause frames on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_rx_drop", "number of dropped rx frames on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_tx_drop", "number of dropped tx frames on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_tx_collision", "number of tx collisions on switch port", labelNames),
+		metrics.BuildMetricDescription(prefix, "port_policy_drop_packet", "number of packets dropped by policer on switch port", labelNames),
+	}, got)
+}
+
+func Test_switchPortCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/switch/print",
+					"=.proplist=name,type",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name": "switch1",
+								"type": "98DX3236",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/switch/port/print",
+					"=stats=",
+					"=.proplist=name,switch,rx-fcs-error,rx-align-error,rx-fragment,rx-overflow,rx-too-short,rx-too-long,rx-pause,tx-pause,rx-drop,tx-drop,tx-collision,policy-drop-packet",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":               "ether1",
+								"switch":             "switch1",
+								"rx-fcs-error":       "3",
+								"tx-pause":           "10",
+								"policy-drop-packet": "7",
+								"rx-drop":            "a1",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "info", "switch chip info", []string{"name", "address", "switch", "type"}),
+					prometheus.GaugeValue, 1, "device", "address", "switch1", "98DX3236",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "port_rx_fcs_error", "number of rx frames with fcs errors on switch port", labelNames),
+					prometheus.CounterValue, 3, "device", "address", "switch1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "port_tx_pause", "number of tx pause frames on switch port", labelNames),
+					prometheus.CounterValue, 10, "device", "address", "switch1", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "port_policy_drop_packet", "number of packets dropped by policer on switch port", labelNames),
+					prometheus.CounterValue, 7, "device", "address", "switch1", "ether1",
+				),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/switch/print",
+					"=.proplist=name,type",
+				}...).Then(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ethernet/switch/port/print",
+					"=stats=",
+					"=.proplist=name,switch,rx-fcs-error,rx-align-error,rx-fragment,rx-overflow,rx-too-short,rx-too-long,rx-pause,tx-pause,rx-drop,tx-drop,tx-collision,policy-drop-packet",
+				}...).Then(&routeros.Reply{}, nil)
+			},
+			errWant: "failed to fetch switches: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
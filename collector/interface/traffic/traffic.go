@@ -0,0 +1,105 @@
+package traffic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	properties = []string{
+		"name",
+		"rx-bits-per-second", "tx-bits-per-second", "rx-packets-per-second", "tx-packets-per-second",
+		"fp-rx-bits-per-second", "fp-tx-bits-per-second", "fp-rx-packets-per-second", "fp-tx-packets-per-second",
+	}
+	labelNames         = []string{"name", "address", "interface"}
+	metricDescriptions = map[string]*prometheus.Desc{
+		"rx-bits-per-second":       metrics.BuildMetricDescription(prefix, "rx_bits_per_second", "current rx rate in bits per second", labelNames),
+		"tx-bits-per-second":       metrics.BuildMetricDescription(prefix, "tx_bits_per_second", "current tx rate in bits per second", labelNames),
+		"rx-packets-per-second":    metrics.BuildMetricDescription(prefix, "rx_packets_per_second", "current rx rate in packets per second", labelNames),
+		"tx-packets-per-second":    metrics.BuildMetricDescription(prefix, "tx_packets_per_second", "current tx rate in packets per second", labelNames),
+		"fp-rx-bits-per-second":    metrics.BuildMetricDescription(prefix, "fp_rx_bits_per_second", "current fast-path rx rate in bits per second", labelNames),
+		"fp-tx-bits-per-second":    metrics.BuildMetricDescription(prefix, "fp_tx_bits_per_second", "current fast-path tx rate in bits per second", labelNames),
+		"fp-rx-packets-per-second": metrics.BuildMetricDescription(prefix, "fp_rx_packets_per_second", "current fast-path rx rate in packets per second", labelNames),
+		"fp-tx-packets-per-second": metrics.BuildMetricDescription(prefix, "fp_tx_packets_per_second", "current fast-path tx rate in packets per second", labelNames),
+	}
+)
+
+const prefix = "interface_traffic"
+
+type trafficCollector struct {
+	interfaces []string
+}
+
+// NewCollector - monitors the given interfaces only, as monitor-traffic of every interface is expensive on devices
+// with many, e.g. PPPoE, interfaces
+func NewCollector(interfaces ...string) *trafficCollector {
+	return &trafficCollector{
+		interfaces: interfaces,
+	}
+}
+
+func (c *trafficCollector) Name() string {
+	return prefix
+}
+
+func (c *trafficCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range metricDescriptions {
+		ch <- d
+	}
+}
+
+func (c *trafficCollector) Collect(ctx *context.Context) error {
+	if len(c.interfaces) == 0 {
+		return nil
+	}
+
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/monitor-traffic",
+		"=interface="+strings.Join(c.interfaces, ","),
+		"=once=",
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch interface traffic: %w", err)
+	}
+
+	for _, re := range reply.Re {
+		c.collectMetricsForInterface(re, ctx)
+	}
+
+	return nil
+}
+
+func (c *trafficCollector) collectMetricsForInterface(re *proto.Sentence, ctx *context.Context) {
+	for property, desc := range metricDescriptions {
+		value := re.Map[property]
+		if len(value) == 0 {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"interface": re.Map["name"],
+				"property":  property,
+				"value":     value,
+				"error":     err,
+			}).Error("failed to parse interface traffic metric value")
+			continue
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, re.Map["name"],
+		)
+	}
+}
@@ -0,0 +1,199 @@
+package traffic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_trafficCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("interface_traffic", c.Name())
+}
+
+func Test_trafficCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "rx_bits_per_second", "current rx rate in bits per second", labelNames),
+		metrics.BuildMetricDescription(prefix, "tx_bits_per_second", "current tx rate in bits per second", labelNames),
+		metrics.BuildMetricDescription(prefix, "rx_packets_per_second", "current rx rate in packets per second", labelNames),
+		metrics.BuildMetricDescription(prefix, "tx_packets_per_second", "current tx rate in packets per second", labelNames),
+		metrics.BuildMetricDescription(prefix, "fp_rx_bits_per_second", "current fast-path rx rate in bits per second", labelNames),
+		metrics.BuildMetricDescription(prefix, "fp_tx_bits_per_second", "current fast-path tx rate in bits per second", labelNames),
+		metrics.BuildMetricDescription(prefix, "fp_rx_packets_per_second", "current fast-path rx rate in packets per second", labelNames),
+		metrics.BuildMetricDescription(prefix, "fp_tx_packets_per_second", "current fast-path tx rate in packets per second", labelNames),
+	}, got)
+}
+
+func Test_trafficCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	testCases := []struct {
+		name       string
+		interfaces []string
+		setMocks   func()
+		want       []prometheus.Metric
+		errWant    string
+	}{
+		{
+			name:       "success",
+			interfaces: []string{"ether1", "ether2"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/monitor-traffic",
+					"=interface=ether1,ether2",
+					"=once=",
+					"=.proplist=name,rx-bits-per-second,tx-bits-per-second,rx-packets-per-second,tx-packets-per-second,fp-rx-bits-per-second,fp-tx-bits-per-second,fp-rx-packets-per-second,fp-tx-packets-per-second",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":                  "ether1",
+								"rx-bits-per-second":    "1024000",
+								"tx-bits-per-second":    "512000",
+								"fp-rx-bits-per-second": "1000000",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":                  "ether2",
+								"rx-packets-per-second": "a10",
+								"tx-packets-per-second": "20",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "rx_bits_per_second", "current rx rate in bits per second", labelNames),
+					prometheus.GaugeValue, 1024000, "device", "address", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "tx_bits_per_second", "current tx rate in bits per second", labelNames),
+					prometheus.GaugeValue, 512000, "device", "address", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "fp_rx_bits_per_second", "current fast-path rx rate in bits per second", labelNames),
+					prometheus.GaugeValue, 1000000, "device", "address", "ether1",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "tx_packets_per_second", "current tx rate in packets per second", labelNames),
+					prometheus.GaugeValue, 20, "device", "address", "ether2",
+				),
+			},
+		},
+		{
+			name:       "success for single interface",
+			interfaces: []string{"vlan10"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/monitor-traffic",
+					"=interface=vlan10",
+					"=once=",
+					"=.proplist=name,rx-bits-per-second,tx-bits-per-second,rx-packets-per-second,tx-packets-per-second,fp-rx-bits-per-second,fp-tx-bits-per-second,fp-rx-packets-per-second,fp-tx-packets-per-second",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":                     "vlan10",
+								"fp-tx-packets-per-second": "300",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "fp_tx_packets_per_second", "current fast-path tx rate in packets per second", labelNames),
+					prometheus.GaugeValue, 300, "device", "address", "vlan10",
+				),
+			},
+		},
+		{
+			name:     "no interfaces",
+			setMocks: func() {},
+		},
+		{
+			name:       "fetch traffic error",
+			interfaces: []string{"ether1"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/monitor-traffic",
+					"=interface=ether1",
+					"=once=",
+					"=.proplist=name,rx-bits-per-second,tx-bits-per-second,rx-packets-per-second,tx-packets-per-second,fp-rx-bits-per-second,fp-tx-bits-per-second,fp-rx-packets-per-second,fp-tx-packets-per-second",
+				}...).Then(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch interface traffic: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCollector(tc.interfaces...)
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
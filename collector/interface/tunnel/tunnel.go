@@ -0,0 +1,150 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/parsers"
+)
+
+var (
+	tunnelTypes        = []string{"gre", "eoip", "ipip"}
+	properties         = []string{"name", "running", "local-address", "remote-address", "keepalive"}
+	labelNames         = []string{"name", "address", "tunnel", "type", "local_address", "remote_address"}
+	metricDescriptions = map[string]*metrics.MetricDescription{
+		"running": {
+			Desc:      metrics.BuildMetricDescription(prefix, "up", "tunnel status (up = 1)", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"keepalive-interval": {
+			Desc:      metrics.BuildMetricDescription(prefix, "keepalive_interval_seconds", "tunnel keepalive interval in seconds", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+		"keepalive-retries": {
+			Desc:      metrics.BuildMetricDescription(prefix, "keepalive_retries", "number of tunnel keepalive retries before the tunnel is marked down", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+	}
+)
+
+const prefix = "tunnel"
+
+type tunnelCollector struct{}
+
+func NewCollector() *tunnelCollector {
+	return &tunnelCollector{}
+}
+
+func (c *tunnelCollector) Name() string {
+	return prefix
+}
+
+func (c *tunnelCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range metricDescriptions {
+		ch <- d.Desc
+	}
+}
+
+func (c *tunnelCollector) Collect(ctx *context.Context) error {
+	eg := errgroup.Group{}
+	for i := range tunnelTypes {
+		t := tunnelTypes[i]
+		eg.Go(func() error {
+			return c.collectForType(t, ctx)
+		})
+	}
+
+	return eg.Wait()
+}
+
+func (c *tunnelCollector) collectForType(tunnelType string, ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		fmt.Sprintf("/interface/%s/print", tunnelType),
+		"?disabled=false",
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		// the device rejects the command if the menu doesn't exist, e.g. without the package providing it,
+		// which must not hide the tunnels of the other types
+		var deviceErr *routeros.DeviceError
+		if errors.As(err, &deviceErr) {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"type":      tunnelType,
+				"error":     err,
+			}).Warn("skipping unavailable tunnel menu")
+			return nil
+		}
+
+		return fmt.Errorf("failed to fetch %s tunnels: %w", tunnelType, err)
+	}
+
+	for _, re := range reply.Re {
+		c.collectForStat(tunnelType, re, ctx)
+	}
+
+	return nil
+}
+
+func (c *tunnelCollector) collectForStat(tunnelType string, re *proto.Sentence, ctx *context.Context) {
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, re.Map["name"], tunnelType, re.Map["local-address"], re.Map["remote-address"]}
+
+	var up float64
+	if re.Map["running"] == "true" {
+		up = 1
+	}
+
+	desc := metricDescriptions["running"]
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, up, labelValues...)
+
+	// keepalive is reported as "<interval>,<retries>" and is empty when disabled
+	value := re.Map["keepalive"]
+	if len(value) == 0 {
+		return
+	}
+
+	parts := strings.Split(value, ",")
+	interval, err := parsers.ParseDuration(parts[0])
+	if err != nil {
+		c.logParseError(re, "keepalive", value, err, ctx)
+		return
+	}
+
+	desc = metricDescriptions["keepalive-interval"]
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, interval, labelValues...)
+
+	if len(parts) < 2 {
+		return
+	}
+
+	retries, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		c.logParseError(re, "keepalive", value, err, ctx)
+		return
+	}
+
+	desc = metricDescriptions["keepalive-retries"]
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, retries, labelValues...)
+}
+
+func (c *tunnelCollector) logParseError(re *proto.Sentence, property, value string, err error, ctx *context.Context) {
+	log.WithFields(log.Fields{
+		"collector": c.Name(),
+		"device":    ctx.DeviceName,
+		"tunnel":    re.Map["name"],
+		"property":  property,
+		"value":     value,
+		"error":     err,
+	}).Error("failed to parse tunnel metric value")
+}
@@ -0,0 +1,236 @@
+package tunnel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_tunnelCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("tunnel", c.Name())
+}
+
+func Test_tunnelCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "up", "tunnel status (up = 1)", labelNames),
+		metrics.BuildMetricDescription(prefix, "keepalive_interval_seconds", "tunnel keepalive interval in seconds", labelNames),
+		metrics.BuildMetricDescription(prefix, "keepalive_retries", "number of tunnel keepalive retries before the tunnel is marked down", labelNames),
+	}, got)
+}
+
+func Test_tunnelCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/gre/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":           "gre-site1",
+								"running":        "true",
+								"local-address":  "10.0.0.1",
+								"remote-address": "10.0.0.2",
+								"keepalive":      "10s,5",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/eoip/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":           "eoip-site2",
+								"running":        "false",
+								"remote-address": "10.0.1.2",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ipip/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":           "ipip-site3",
+								"running":        "true",
+								"remote-address": "10.0.2.2",
+								"keepalive":      "a10s,5",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "up", "tunnel status (up = 1)", labelNames),
+					prometheus.GaugeValue, 1, "device", "address", "gre-site1", "gre", "10.0.0.1", "10.0.0.2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "keepalive_interval_seconds", "tunnel keepalive interval in seconds", labelNames),
+					prometheus.GaugeValue, 10, "device", "address", "gre-site1", "gre", "10.0.0.1", "10.0.0.2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "keepalive_retries", "number of tunnel keepalive retries before the tunnel is marked down", labelNames),
+					prometheus.GaugeValue, 5, "device", "address", "gre-site1", "gre", "10.0.0.1", "10.0.0.2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "up", "tunnel status (up = 1)", labelNames),
+					prometheus.GaugeValue, 0, "device", "address", "eoip-site2", "eoip", "", "10.0.1.2",
+				),
+				prometheus.MustNewConstMetric(
+					metrics.BuildMetricDescription(prefix, "up", "tunnel status (up = 1)", labelNames),
+					prometheus.GaugeValue, 1, "device", "address", "ipip-site3", "ipip", "", "10.0.2.2",
+				),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/gre/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/eoip/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ipip/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{}, nil)
+			},
+			errWant: "failed to fetch gre tunnels: some fetch error",
+		},
+		{
+			name: "missing menu",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/gre/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(nil, &routeros.DeviceError{
+					Sentence: &proto.Sentence{Map: map[string]string{"message": "no such command prefix"}},
+				})
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/eoip/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":           "eoip1",
+								"running":        "true",
+								"local-address":  "10.0.0.1",
+								"remote-address": "10.0.0.2",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/ipip/print",
+					"?disabled=false",
+					"=.proplist=name,running,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(metricDescriptions["running"].Desc, prometheus.GaugeValue, 1,
+					"device", "address", "eoip1", "eoip", "10.0.0.1", "10.0.0.2",
+				),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
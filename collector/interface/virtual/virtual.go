@@ -0,0 +1,166 @@
+package virtual
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+// interfaceType - describes how properties of a virtual interface menu map to the exported labels
+type interfaceType struct {
+	name                  string
+	parentProperty        string
+	idProperty            string
+	localAddressProperty  string
+	remoteAddressProperty string
+	keepaliveProperty     string
+}
+
+var (
+	interfaceTypes = []*interfaceType{
+		{
+			name:           "vlan",
+			parentProperty: "interface",
+			idProperty:     "vlan-id",
+		},
+		{
+			name:                  "eoip",
+			idProperty:            "tunnel-id",
+			localAddressProperty:  "local-address",
+			remoteAddressProperty: "remote-address",
+			keepaliveProperty:     "keepalive",
+		},
+		{
+			name:                  "gre",
+			localAddressProperty:  "local-address",
+			remoteAddressProperty: "remote-address",
+			keepaliveProperty:     "keepalive",
+		},
+		{
+			name:                  "ipip",
+			localAddressProperty:  "local-address",
+			remoteAddressProperty: "remote-address",
+			keepaliveProperty:     "keepalive",
+		},
+		{
+			name:                  "vxlan",
+			parentProperty:        "interface",
+			idProperty:            "vni",
+			localAddressProperty:  "local-address",
+			remoteAddressProperty: "group",
+		},
+	}
+	labelNames            = []string{"name", "address", "interface", "type"}
+	infoMetricDescription = metrics.BuildMetricDescription(prefix, "info", "virtual interface configuration",
+		append(labelNames, "parent", "id", "local_address", "remote_address", "keepalive"),
+	)
+	mtuMetricDescription = metrics.BuildMetricDescription(prefix, "actual_mtu", "actual mtu of virtual interface", labelNames)
+)
+
+const prefix = "virtual_interface"
+
+type virtualInterfaceCollector struct{}
+
+func NewCollector() *virtualInterfaceCollector {
+	return &virtualInterfaceCollector{}
+}
+
+func (c *virtualInterfaceCollector) Name() string {
+	return prefix
+}
+
+func (c *virtualInterfaceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- infoMetricDescription
+	ch <- mtuMetricDescription
+}
+
+func (c *virtualInterfaceCollector) Collect(ctx *context.Context) error {
+	eg := errgroup.Group{}
+	for i := range interfaceTypes {
+		t := interfaceTypes[i]
+		eg.Go(func() error {
+			return c.collectForType(t, ctx)
+		})
+	}
+
+	return eg.Wait()
+}
+
+func (c *virtualInterfaceCollector) collectForType(t *interfaceType, ctx *context.Context) error {
+	properties := []string{"name", "actual-mtu"}
+	for _, p := range []string{t.parentProperty, t.idProperty, t.localAddressProperty, t.remoteAddressProperty, t.keepaliveProperty} {
+		if len(p) != 0 {
+			properties = append(properties, p)
+		}
+	}
+
+	reply, err := ctx.RouterOSClient.Run(
+		fmt.Sprintf("/interface/%s/print", t.name),
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		// the device rejects the command if the menu doesn't exist, e.g. /interface/vxlan on RouterOS v6,
+		// which must not hide the interfaces of the other types
+		var deviceErr *routeros.DeviceError
+		if errors.As(err, &deviceErr) {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"type":      t.name,
+				"error":     err,
+			}).Warn("skipping unavailable virtual interface menu")
+			return nil
+		}
+
+		return fmt.Errorf("failed to fetch %s interfaces: %w", t.name, err)
+	}
+
+	for _, re := range reply.Re {
+		c.collectForStat(t, re, ctx)
+	}
+
+	return nil
+}
+
+func (c *virtualInterfaceCollector) collectForStat(t *interfaceType, re *proto.Sentence, ctx *context.Context) {
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(infoMetricDescription, prometheus.GaugeValue, 1,
+		ctx.DeviceName, ctx.DeviceAddress, re.Map["name"], t.name,
+		re.Map[t.parentProperty],
+		re.Map[t.idProperty],
+		re.Map[t.localAddressProperty],
+		re.Map[t.remoteAddressProperty],
+		re.Map[t.keepaliveProperty],
+	)
+
+	value := re.Map["actual-mtu"]
+	if len(value) == 0 {
+		return
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector": c.Name(),
+			"device":    ctx.DeviceName,
+			"interface": re.Map["name"],
+			"property":  "actual-mtu",
+			"value":     value,
+			"error":     err,
+		}).Error("failed to parse virtual interface metric value")
+		return
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(mtuMetricDescription, prometheus.GaugeValue, v,
+		ctx.DeviceName, ctx.DeviceAddress, re.Map["name"], t.name,
+	)
+}
@@ -0,0 +1,221 @@
+package virtual
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_virtualInterfaceCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("virtual_interface", c.Name())
+}
+
+func Test_virtualInterfaceCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "info", "virtual interface configuration",
+			append(labelNames, "parent", "id", "local_address", "remote_address", "keepalive"),
+		),
+		metrics.BuildMetricDescription(prefix, "actual_mtu", "actual mtu of virtual interface", labelNames),
+	}, got)
+}
+
+func Test_virtualInterfaceCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	infoDesc := metrics.BuildMetricDescription(prefix, "info", "virtual interface configuration",
+		append(labelNames, "parent", "id", "local_address", "remote_address", "keepalive"),
+	)
+	mtuDesc := metrics.BuildMetricDescription(prefix, "actual_mtu", "actual mtu of virtual interface", labelNames)
+
+	setEmptyMocks := func(types ...string) {
+		sentences := map[string][]string{
+			"eoip":  {"/interface/eoip/print", "=.proplist=name,actual-mtu,tunnel-id,local-address,remote-address,keepalive"},
+			"ipip":  {"/interface/ipip/print", "=.proplist=name,actual-mtu,local-address,remote-address,keepalive"},
+			"vxlan": {"/interface/vxlan/print", "=.proplist=name,actual-mtu,interface,vni,local-address,group"},
+		}
+		for _, t := range types {
+			routerOSClientMock.RunMock.When(sentences[t]...).Then(&routeros.Reply{}, nil)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				setEmptyMocks("eoip", "ipip", "vxlan")
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/vlan/print",
+					"=.proplist=name,actual-mtu,interface,vlan-id",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":       "vlan10",
+								"actual-mtu": "1500",
+								"interface":  "bridge",
+								"vlan-id":    "10",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/gre/print",
+					"=.proplist=name,actual-mtu,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":           "gre-site1",
+								"actual-mtu":     "a1476",
+								"local-address":  "10.0.0.1",
+								"remote-address": "10.0.0.2",
+								"keepalive":      "10s,10",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "vlan10", "vlan", "bridge", "10", "", "", "",
+				),
+				prometheus.MustNewConstMetric(mtuDesc, prometheus.GaugeValue, 1500,
+					"device", "address", "vlan10", "vlan",
+				),
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "gre-site1", "gre", "", "", "10.0.0.1", "10.0.0.2", "10s,10",
+				),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				setEmptyMocks("eoip", "ipip", "vxlan")
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/vlan/print",
+					"=.proplist=name,actual-mtu,interface,vlan-id",
+				}...).Then(&routeros.Reply{}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/gre/print",
+					"=.proplist=name,actual-mtu,local-address,remote-address,keepalive",
+				}...).Then(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch gre interfaces: some fetch error",
+		},
+		{
+			name: "unavailable menu",
+			setMocks: func() {
+				setEmptyMocks("eoip", "ipip")
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/vlan/print",
+					"=.proplist=name,actual-mtu,interface,vlan-id",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":       "vlan10",
+								"actual-mtu": "1500",
+								"interface":  "bridge",
+								"vlan-id":    "10",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/gre/print",
+					"=.proplist=name,actual-mtu,local-address,remote-address,keepalive",
+				}...).Then(&routeros.Reply{}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/interface/vxlan/print",
+					"=.proplist=name,actual-mtu,interface,vni,local-address,group",
+				}...).Then(nil, &routeros.DeviceError{
+					Sentence: &proto.Sentence{Map: map[string]string{"message": "no such command prefix"}},
+				})
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "vlan10", "vlan", "bridge", "10", "", "", "",
+				),
+				prometheus.MustNewConstMetric(mtuDesc, prometheus.GaugeValue, 1500,
+					"device", "address", "vlan10", "vlan",
+				),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
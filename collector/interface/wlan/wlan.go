@@ -10,6 +10,7 @@ import (
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
 )
 
@@ -23,12 +24,32 @@ var (
 	}
 )
 
-const prefix = "wlan_interface"
+const (
+	prefix        = "wlan_interface"
+	interfaceType = "wlan"
+)
+
+type wlanInterfaceCollector struct {
+	filter *filter.Filter
+}
 
-type wlanInterfaceCollector struct{}
+// Option - represents a function on wlan interface collector instance
+type Option func(*wlanInterfaceCollector)
 
-func NewCollector() *wlanInterfaceCollector {
-	return &wlanInterfaceCollector{}
+// WithFilter - limits metrics to the interfaces matching the filter
+func WithFilter(f *filter.Filter) Option {
+	return func(c *wlanInterfaceCollector) {
+		c.filter = f
+	}
+}
+
+func NewCollector(opts ...Option) *wlanInterfaceCollector {
+	c := &wlanInterfaceCollector{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
 }
 
 func (c *wlanInterfaceCollector) Name() string {
@@ -41,11 +62,16 @@ func (c *wlanInterfaceCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
+// Supported - the legacy wireless menus are only available with the wireless package installed
+func (c *wlanInterfaceCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayHavePackage("wireless")
+}
+
 func (c *wlanInterfaceCollector) Collect(ctx *context.Context) error {
 	reply, err := ctx.RouterOSClient.Run(
 		"/interface/wireless/print",
 		"?disabled=false",
-		"=.proplist=name",
+		"=.proplist="+strings.Join(c.filter.AppendProperties([]string{"name"}), ","),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch wlan interface names: %w", err)
@@ -53,7 +79,13 @@ func (c *wlanInterfaceCollector) Collect(ctx *context.Context) error {
 
 	interfaces := make([]string, 0, len(reply.Re))
 	for _, re := range reply.Re {
-		interfaces = append(interfaces, re.Map["name"])
+		if c.filter.Match(re, interfaceType) {
+			interfaces = append(interfaces, re.Map["name"])
+		}
+	}
+
+	if len(interfaces) == 0 {
+		return nil
 	}
 
 	return c.collectForInterfaces(interfaces, ctx)
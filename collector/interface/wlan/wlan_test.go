@@ -48,6 +48,16 @@ func Test_wlanInterfaceCollector_Describe(t *testing.T) {
 	}, got)
 }
 
+func Test_wlanInterfaceCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Packages: []string{"routeros", "wireless"}}))
+	r.False(c.Supported(&context.Capabilities{Packages: []string{"routeros", "wifi-qcom"}}))
+}
+
 func Test_wlanInterfaceCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
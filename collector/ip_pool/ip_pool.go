@@ -2,18 +2,26 @@ package ip_pool
 
 import (
 	"fmt"
+	"math"
+	"net/netip"
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
 	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/parsers"
 )
 
-var metricDescription = metrics.BuildMetricDescription(prefix, "used", "number of used ip/prefixes in pool",
-	[]string{"name", "address", "ip_version", "pool"},
+var (
+	labelNames = []string{"name", "address", "ip_version", "pool"}
+
+	metricDescription            = metrics.BuildMetricDescription(prefix, "used", "number of used ip/prefixes in pool", labelNames)
+	sizeMetricDescription        = metrics.BuildMetricDescription(prefix, "size", "number of ip/prefixes in pool", labelNames)
+	utilizationMetricDescription = metrics.BuildMetricDescription(prefix, "utilization_ratio", "ratio of used to total ip/prefixes in pool (0-1)", labelNames)
 )
 
 const prefix = "ip_pool"
@@ -30,33 +38,78 @@ func (c *ipPoolCollector) Name() string {
 
 func (c *ipPoolCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- metricDescription
+	ch <- sizeMetricDescription
+	ch <- utilizationMetricDescription
 }
 
 func (c *ipPoolCollector) Collect(ctx *context.Context) error {
-	return c.collectForIPVersion("4", "ip", ctx)
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		return c.collectForIPVersion("4", "ip", ctx)
+	})
+
+	// RouterOS v6 ships IPv6 support in a separate package, which may be disabled
+	if ctx.Capabilities.MajorVersion() != 6 || ctx.Capabilities.MayHavePackage("ipv6") {
+		eg.Go(func() error {
+			return c.collectForIPVersion("6", "ipv6", ctx)
+		})
+	}
+
+	return eg.Wait()
 }
 
 func (c *ipPoolCollector) collectForIPVersion(ipVersion, topic string, ctx *context.Context) error {
+	proplist := "=.proplist=name,ranges"
+	if ipVersion == "6" {
+		proplist = "=.proplist=name,prefix,prefix-length"
+	}
+
 	reply, err := ctx.RouterOSClient.Run(
 		fmt.Sprintf("/%s/pool/print", topic),
-		"=.proplist=name",
+		proplist,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to fetch ip pool names: %w", err)
+		return fmt.Errorf("failed to fetch %s pool names: %w", topic, err)
 	}
 
 	eg := errgroup.Group{}
 	for _, re := range reply.Re {
-		m := re.Map
+		size := c.poolSize(ipVersion, re, ctx)
+		name := re.Map["name"]
 		eg.Go(func() error {
-			return c.collectForPool(ipVersion, topic, m["name"], ctx)
+			return c.collectForPool(ipVersion, topic, name, size, ctx)
 		})
 	}
 
 	return eg.Wait()
 }
 
-func (c *ipPoolCollector) collectForPool(ipVersion, topic, pool string, ctx *context.Context) error {
+// poolSize - returns the number of addresses (IPv4) or prefixes (IPv6) in pool or 0 if unknown
+func (c *ipPoolCollector) poolSize(ipVersion string, re *proto.Sentence, ctx *context.Context) float64 {
+	var (
+		size float64
+		err  error
+	)
+	if ipVersion == "6" {
+		size, err = prefixPoolSize(re.Map["prefix"], re.Map["prefix-length"])
+	} else {
+		size, err = parsers.ParseIPRangesSize(re.Map["ranges"])
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector":  c.Name(),
+			"ip_pool":    re.Map["name"],
+			"ip_version": ipVersion,
+			"device":     ctx.DeviceName,
+			"error":      err,
+		}).Error("failed to parse ip pool size")
+		return 0
+	}
+
+	return size
+}
+
+func (c *ipPoolCollector) collectForPool(ipVersion, topic, pool string, size float64, ctx *context.Context) error {
 	reply, err := ctx.RouterOSClient.Run(
 		fmt.Sprintf("/%s/pool/used/print", topic),
 		fmt.Sprintf("?pool=%s", pool),
@@ -66,6 +119,11 @@ func (c *ipPoolCollector) collectForPool(ipVersion, topic, pool string, ctx *con
 		return fmt.Errorf("failed to fetch pool used info: %w", err)
 	}
 
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, ipVersion, pool}
+	if size > 0 {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(sizeMetricDescription, prometheus.GaugeValue, size, labelValues...)
+	}
+
 	value := reply.Done.Map["ret"]
 	if len(value) == 0 {
 		return nil
@@ -83,9 +141,33 @@ func (c *ipPoolCollector) collectForPool(ipVersion, topic, pool string, ctx *con
 		return nil
 	}
 
-	ctx.MetricsChan <- prometheus.MustNewConstMetric(metricDescription, prometheus.GaugeValue, v,
-		ctx.DeviceName, ctx.DeviceAddress, ipVersion, pool,
-	)
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(metricDescription, prometheus.GaugeValue, v, labelValues...)
+
+	if size > 0 {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(utilizationMetricDescription, prometheus.GaugeValue, v/size, labelValues...)
+	}
 
 	return nil
 }
+
+// prefixPoolSize - returns the number of prefixes of prefixLength that fit into IPv6 pool prefix
+func prefixPoolSize(poolPrefix, prefixLength string) (float64, error) {
+	if len(poolPrefix) == 0 || len(prefixLength) == 0 {
+		return 0, nil
+	}
+
+	p, err := netip.ParsePrefix(poolPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	length, err := strconv.Atoi(prefixLength)
+	if err != nil {
+		return 0, err
+	}
+	if length < p.Bits() || length > p.Addr().BitLen() {
+		return 0, fmt.Errorf("prefix length %d does not fit into %s", length, poolPrefix)
+	}
+
+	return math.Pow(2, float64(length-p.Bits())), nil
+}
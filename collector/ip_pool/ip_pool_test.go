@@ -45,6 +45,12 @@ func Test_ipPoolCollector_Describe(t *testing.T) {
 		metrics.BuildMetricDescription(prefix, "used", "number of used ip/prefixes in pool",
 			[]string{"name", "address", "ip_version", "pool"},
 		),
+		metrics.BuildMetricDescription(prefix, "size", "number of ip/prefixes in pool",
+			[]string{"name", "address", "ip_version", "pool"},
+		),
+		metrics.BuildMetricDescription(prefix, "utilization_ratio", "ratio of used to total ip/prefixes in pool (0-1)",
+			[]string{"name", "address", "ip_version", "pool"},
+		),
 	}, got)
 }
 
@@ -59,22 +65,90 @@ func Test_ipPoolCollector_Collect(t *testing.T) {
 	}
 
 	testCases := []struct {
-		name     string
-		setMocks func()
-		want     []prometheus.Metric
-		errWant  string
+		name         string
+		capabilities *context.Capabilities
+		setMocks     func()
+		want         []prometheus.Metric
+		errWant      string
 	}{
 		{
 			name: "success",
 			setMocks: func() {
 				routerOSClientMock.RunMock.When([]string{
 					"/ip/pool/print",
-					"=.proplist=name",
+					"=.proplist=name,ranges",
 				}...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
 						{
 							Map: map[string]string{
-								"name": "pool1",
+								"name":   "pool1",
+								"ranges": "10.0.0.10-10.0.0.19,10.0.1.0/28,10.0.2.1",
+							},
+						},
+					},
+				}, nil)
+
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/pool/used/print",
+					"?pool=pool1",
+					"=count-only=",
+				}...).Then(&routeros.Reply{
+					Done: &proto.Sentence{
+						Map: map[string]string{
+							"ret": "3",
+						},
+					},
+				}, nil)
+
+				routerOSClientMock.RunMock.When([]string{
+					"/ipv6/pool/print",
+					"=.proplist=name,prefix,prefix-length",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":          "pd",
+								"prefix":        "2001:db8::/48",
+								"prefix-length": "56",
+							},
+						},
+					},
+				}, nil)
+
+				routerOSClientMock.RunMock.When([]string{
+					"/ipv6/pool/used/print",
+					"?pool=pd",
+					"=count-only=",
+				}...).Then(&routeros.Reply{
+					Done: &proto.Sentence{
+						Map: map[string]string{
+							"ret": "64",
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(metricDescription, prometheus.GaugeValue, 3, "device", "address", "4", "pool1"),
+				prometheus.MustNewConstMetric(sizeMetricDescription, prometheus.GaugeValue, 27, "device", "address", "4", "pool1"),
+				prometheus.MustNewConstMetric(utilizationMetricDescription, prometheus.GaugeValue, 3.0/27, "device", "address", "4", "pool1"),
+				prometheus.MustNewConstMetric(metricDescription, prometheus.GaugeValue, 64, "device", "address", "6", "pd"),
+				prometheus.MustNewConstMetric(sizeMetricDescription, prometheus.GaugeValue, 256, "device", "address", "6", "pd"),
+				prometheus.MustNewConstMetric(utilizationMetricDescription, prometheus.GaugeValue, 0.25, "device", "address", "6", "pd"),
+			},
+		},
+		{
+			name:         "v6 without ipv6 package",
+			capabilities: &context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system"}},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/pool/print",
+					"=.proplist=name,ranges",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":   "pool1",
+								"ranges": "invalid",
 							},
 						},
 					},
@@ -93,32 +167,31 @@ func Test_ipPoolCollector_Collect(t *testing.T) {
 				}, nil)
 			},
 			want: []prometheus.Metric{
-				prometheus.MustNewConstMetric(
-					metrics.BuildMetricDescription(prefix, "used", "number of used ip/prefixes in pool",
-						[]string{"name", "address", "ip_version", "pool"},
-					),
-					prometheus.GaugeValue, 3, "device", "address", "4", "pool1",
-				),
+				prometheus.MustNewConstMetric(metricDescription, prometheus.GaugeValue, 3, "device", "address", "4", "pool1"),
 			},
 		},
 		{
 			name: "fetch error",
 			setMocks: func() {
-				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
-					r.Equal([]string{
-						"/ip/pool/print",
-						"=.proplist=name",
-					}, sentence)
-				}).Return(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/pool/print",
+					"=.proplist=name,ranges",
+				}...).Then(nil, errors.New("some fetch error"))
+
+				routerOSClientMock.RunMock.When([]string{
+					"/ipv6/pool/print",
+					"=.proplist=name,prefix,prefix-length",
+				}...).Then(&routeros.Reply{}, nil)
 			},
 			errWant: "failed to fetch ip pool names: some fetch error",
 		},
 		{
-			name: "parse error",
+			name:         "parse error",
+			capabilities: &context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system"}},
 			setMocks: func() {
 				routerOSClientMock.RunMock.When([]string{
 					"/ip/pool/print",
-					"=.proplist=name",
+					"=.proplist=name,ranges",
 				}...).Then(&routeros.Reply{
 					Re: []*proto.Sentence{
 						{
@@ -166,6 +239,7 @@ func Test_ipPoolCollector_Collect(t *testing.T) {
 				MetricsChan:    ch,
 				DeviceName:     "device",
 				DeviceAddress:  "address",
+				Capabilities:   tc.capabilities,
 			})
 			close(ch)
 			if len(tc.errWant) != 0 {
@@ -179,3 +253,31 @@ func Test_ipPoolCollector_Collect(t *testing.T) {
 		})
 	}
 }
+
+func Test_prefixPoolSize(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		prefix       string
+		prefixLength string
+		want         float64
+		wantErr      bool
+	}{
+		{prefix: "", prefixLength: "64", want: 0},
+		{prefix: "2001:db8::/48", prefixLength: "64", want: 65536},
+		{prefix: "2001:db8::/64", prefixLength: "64", want: 1},
+		{prefix: "2001:db8::/64", prefixLength: "48", wantErr: true},
+		{prefix: "2001:db8::/48", prefixLength: "x", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := prefixPoolSize(tc.prefix, tc.prefixLength)
+		if tc.wantErr {
+			r.Error(err, tc.prefix)
+			continue
+		}
+
+		r.NoError(err, tc.prefix)
+		r.Equal(tc.want, got, tc.prefix)
+	}
+}
@@ -0,0 +1,170 @@
+package mpls
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	neighborProperties             = []string{"peer", "transport", "local-transport", "addresses", "state"}
+	neighborStates                 = []string{"nonexistent", "initialized", "open-sent", "open-received", "operational"}
+	neighborLabelNames             = []string{"name", "address", "peer", "transport", "local_transport"}
+	neighborStateMetricDescription = metrics.BuildMetricDescription(prefix, "ldp_neighbor_state", "ldp neighbor state (current state = 1)",
+		append(neighborLabelNames, "state"),
+	)
+	neighborAddressesMetricDescription = metrics.BuildMetricDescription(prefix, "ldp_neighbor_addresses", "number of addresses announced by ldp neighbor", neighborLabelNames)
+	neighborBindingsMetricDescription  = metrics.BuildMetricDescription(prefix, "ldp_neighbor_remote_bindings", "number of labels bound by ldp neighbor", neighborLabelNames)
+	forwardingTableMetricDescription   = metrics.BuildMetricDescription(prefix, "forwarding_table_entries", "number of mpls forwarding table entries",
+		[]string{"name", "address"},
+	)
+)
+
+const prefix = "mpls"
+
+type mplsCollector struct{}
+
+func NewCollector() *mplsCollector {
+	return &mplsCollector{}
+}
+
+func (c *mplsCollector) Name() string {
+	return prefix
+}
+
+func (c *mplsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- neighborStateMetricDescription
+	ch <- neighborAddressesMetricDescription
+	ch <- neighborBindingsMetricDescription
+	ch <- forwardingTableMetricDescription
+}
+
+// Supported - RouterOS v6 ships MPLS in the mpls package
+func (c *mplsCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MajorVersion() != 6 || caps.MayHavePackage("mpls")
+}
+
+func (c *mplsCollector) Collect(ctx *context.Context) error {
+	var (
+		neighbors []*proto.Sentence
+		bindings  []*proto.Sentence
+	)
+
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		reply, err := ctx.RouterOSClient.Run(
+			"/mpls/ldp/neighbor/print",
+			"=.proplist="+strings.Join(neighborProperties, ","),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to fetch ldp neighbors: %w", err)
+		}
+
+		neighbors = reply.Re
+		return nil
+	})
+
+	eg.Go(func() error {
+		// remote bindings were renamed to remote mappings in RouterOS v7
+		command := "/mpls/ldp/remote-mapping/print"
+		if ctx.Capabilities.MajorVersion() == 6 {
+			command = "/mpls/remote-bindings/print"
+		}
+
+		reply, err := ctx.RouterOSClient.Run(command, "=.proplist=peer")
+		if err != nil {
+			return fmt.Errorf("failed to fetch ldp remote bindings: %w", err)
+		}
+
+		bindings = reply.Re
+		return nil
+	})
+
+	eg.Go(func() error {
+		return c.collectForwardingTable(ctx)
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	bindingsByPeer := make(map[string]float64)
+	for _, re := range bindings {
+		bindingsByPeer[re.Map["peer"]]++
+	}
+
+	for _, re := range neighbors {
+		c.collectForNeighbor(re, bindingsByPeer[re.Map["peer"]], ctx)
+	}
+
+	return nil
+}
+
+func (c *mplsCollector) collectForNeighbor(re *proto.Sentence, bindings float64, ctx *context.Context) {
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, re.Map["peer"], re.Map["transport"], re.Map["local-transport"]}
+
+	if state := re.Map["state"]; len(state) != 0 {
+		known := false
+		for _, s := range neighborStates {
+			var v float64
+			if s == state {
+				v = 1
+				known = true
+			}
+
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, v, append(labelValues, s)...)
+		}
+
+		if !known {
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 1, append(labelValues, state)...)
+		}
+	}
+
+	var addresses float64
+	if value := re.Map["addresses"]; len(value) != 0 {
+		addresses = float64(len(strings.Split(value, ",")))
+	}
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(neighborAddressesMetricDescription, prometheus.GaugeValue, addresses, labelValues...)
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(neighborBindingsMetricDescription, prometheus.GaugeValue, bindings, labelValues...)
+}
+
+func (c *mplsCollector) collectForwardingTable(ctx *context.Context) error {
+	reply, err := ctx.RouterOSClient.Run(
+		"/mpls/forwarding-table/print",
+		"=count-only=",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch mpls forwarding table count: %w", err)
+	}
+
+	value := reply.Done.Map["ret"]
+	if len(value) == 0 {
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector": c.Name(),
+			"device":    ctx.DeviceName,
+			"value":     value,
+			"error":     err,
+		}).Error("failed to parse mpls forwarding table count")
+		return nil
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(forwardingTableMetricDescription, prometheus.GaugeValue, v,
+		ctx.DeviceName, ctx.DeviceAddress,
+	)
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package mpls
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_mplsCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("mpls", c.Name())
+}
+
+func Test_mplsCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "ldp_neighbor_state", "ldp neighbor state (current state = 1)",
+			[]string{"name", "address", "peer", "transport", "local_transport", "state"},
+		),
+		metrics.BuildMetricDescription(prefix, "ldp_neighbor_addresses", "number of addresses announced by ldp neighbor", neighborLabelNames),
+		metrics.BuildMetricDescription(prefix, "ldp_neighbor_remote_bindings", "number of labels bound by ldp neighbor", neighborLabelNames),
+		metrics.BuildMetricDescription(prefix, "forwarding_table_entries", "number of mpls forwarding table entries",
+			[]string{"name", "address"},
+		),
+	}, got)
+}
+
+func Test_mplsCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Version: "7.12.1 (stable)", Packages: []string{"routeros"}}))
+	r.True(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system", "mpls"}}))
+	r.False(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system"}}))
+}
+
+func Test_mplsCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	neighborSentence := []string{
+		"/mpls/ldp/neighbor/print",
+		"=.proplist=peer,transport,local-transport,addresses,state",
+	}
+	forwardingTableSentence := []string{
+		"/mpls/forwarding-table/print",
+		"=count-only=",
+	}
+	neighbors := &routeros.Reply{
+		Re: []*proto.Sentence{
+			{
+				Map: map[string]string{
+					"peer":            "10.0.0.2:0",
+					"transport":       "10.0.0.2",
+					"local-transport": "10.0.0.1",
+					"addresses":       "10.0.0.2,192.168.1.2",
+					"state":           "operational",
+				},
+			},
+			{
+				Map: map[string]string{
+					"peer":            "10.0.0.3:0",
+					"transport":       "10.0.0.3",
+					"local-transport": "10.0.0.1",
+					"state":           "unknown",
+				},
+			},
+		},
+	}
+	bindings := &routeros.Reply{
+		Re: []*proto.Sentence{
+			{Map: map[string]string{"peer": "10.0.0.2:0"}},
+			{Map: map[string]string{"peer": "10.0.0.2:0"}},
+			{Map: map[string]string{"peer": "10.0.0.4:0"}},
+		},
+	}
+	neighborMetrics := []prometheus.Metric{
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.2:0", "10.0.0.2", "10.0.0.1", "nonexistent",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.2:0", "10.0.0.2", "10.0.0.1", "initialized",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.2:0", "10.0.0.2", "10.0.0.1", "open-sent",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.2:0", "10.0.0.2", "10.0.0.1", "open-received",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 1,
+			"device", "address", "10.0.0.2:0", "10.0.0.2", "10.0.0.1", "operational",
+		),
+		prometheus.MustNewConstMetric(neighborAddressesMetricDescription, prometheus.GaugeValue, 2,
+			"device", "address", "10.0.0.2:0", "10.0.0.2", "10.0.0.1",
+		),
+		prometheus.MustNewConstMetric(neighborBindingsMetricDescription, prometheus.GaugeValue, 2,
+			"device", "address", "10.0.0.2:0", "10.0.0.2", "10.0.0.1",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1", "nonexistent",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1", "initialized",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1", "open-sent",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1", "open-received",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1", "operational",
+		),
+		prometheus.MustNewConstMetric(neighborStateMetricDescription, prometheus.GaugeValue, 1,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1", "unknown",
+		),
+		prometheus.MustNewConstMetric(neighborAddressesMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1",
+		),
+		prometheus.MustNewConstMetric(neighborBindingsMetricDescription, prometheus.GaugeValue, 0,
+			"device", "address", "10.0.0.3:0", "10.0.0.3", "10.0.0.1",
+		),
+	}
+
+	testCases := []struct {
+		name         string
+		capabilities *context.Capabilities
+		setMocks     func()
+		want         []prometheus.Metric
+		errWant      string
+	}{
+		{
+			name:         "success",
+			capabilities: &context.Capabilities{Version: "7.12.1 (stable)"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(neighborSentence...).Then(neighbors, nil)
+				routerOSClientMock.RunMock.When("/mpls/ldp/remote-mapping/print", "=.proplist=peer").Then(bindings, nil)
+				routerOSClientMock.RunMock.When(forwardingTableSentence...).Then(&routeros.Reply{
+					Done: &proto.Sentence{Map: map[string]string{"ret": "42"}},
+				}, nil)
+			},
+			want: append([]prometheus.Metric{
+				prometheus.MustNewConstMetric(forwardingTableMetricDescription, prometheus.GaugeValue, 42, "device", "address"),
+			}, neighborMetrics...),
+		},
+		{
+			name:         "success v6",
+			capabilities: &context.Capabilities{Version: "6.49.10 (long-term)"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(neighborSentence...).Then(neighbors, nil)
+				routerOSClientMock.RunMock.When("/mpls/remote-bindings/print", "=.proplist=peer").Then(bindings, nil)
+				routerOSClientMock.RunMock.When(forwardingTableSentence...).Then(&routeros.Reply{
+					Done: &proto.Sentence{Map: map[string]string{"ret": "x"}},
+				}, nil)
+			},
+			want: neighborMetrics,
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When(neighborSentence...).Then(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When("/mpls/ldp/remote-mapping/print", "=.proplist=peer").Then(bindings, nil)
+				routerOSClientMock.RunMock.When(forwardingTableSentence...).Then(&routeros.Reply{
+					Done: &proto.Sentence{Map: map[string]string{"ret": "42"}},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(forwardingTableMetricDescription, prometheus.GaugeValue, 42, "device", "address"),
+			},
+			errWant: "failed to fetch ldp neighbors: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+				Capabilities:   tc.capabilities,
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package ospf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	interfaceProperties = []string{"instance", "area", "interface", "state", "cost", "priority", "network-type", "dr", "bdr"}
+	// v6InterfaceProperties - RouterOS v6 interface properties mapped to their v7 counterparts
+	v6InterfaceProperties = map[string]string{
+		"interface":                "interface",
+		"state":                    "state",
+		"cost":                     "cost",
+		"priority":                 "priority",
+		"network-type":             "network-type",
+		"designated-router":        "dr",
+		"backup-designated-router": "bdr",
+	}
+	v6InterfacePropertyNames = []string{"interface", "state", "cost", "priority", "network-type", "designated-router", "backup-designated-router"}
+	neighborProperties       = []string{"interface", "state"}
+	lsaProperties            = []string{"instance", "area", "type"}
+
+	interfaceLabelNames          = []string{"name", "address", "instance", "area", "interface"}
+	interfaceNumericDescriptions = map[string]*prometheus.Desc{
+		"cost":     metrics.BuildMetricDescription(prefix, "interface_cost", "ospf interface cost", interfaceLabelNames),
+		"priority": metrics.BuildMetricDescription(prefix, "interface_priority", "ospf interface router priority", interfaceLabelNames),
+	}
+	interfaceUpMetricDescription   = metrics.BuildMetricDescription(prefix, "interface_up", "ospf interface is not in down state (up = 1)", interfaceLabelNames)
+	interfaceInfoMetricDescription = metrics.BuildMetricDescription(prefix, "interface_info", "ospf interface state, network type and designated routers",
+		append(interfaceLabelNames, "state", "network_type", "dr", "bdr"),
+	)
+	interfaceNeighborsMetricDescription   = metrics.BuildMetricDescription(prefix, "interface_neighbors", "number of ospf neighbors on interface", interfaceLabelNames)
+	interfaceAdjacenciesMetricDescription = metrics.BuildMetricDescription(prefix, "interface_adjacencies", "number of ospf neighbors in full state on interface", interfaceLabelNames)
+	lsaCountMetricDescription             = metrics.BuildMetricDescription(prefix, "lsa_count", "number of ospf lsa in the database by area and type",
+		[]string{"name", "address", "instance", "area", "type"},
+	)
+)
+
+const prefix = "ospf"
+
+type (
+	ospfCollector struct{}
+
+	// neighborCount - represents neighbor counts of an interface
+	neighborCount struct {
+		neighbors   float64
+		adjacencies float64
+	}
+)
+
+func NewCollector() *ospfCollector {
+	return &ospfCollector{}
+}
+
+func (c *ospfCollector) Name() string {
+	return prefix
+}
+
+func (c *ospfCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range interfaceNumericDescriptions {
+		ch <- d
+	}
+	ch <- interfaceUpMetricDescription
+	ch <- interfaceInfoMetricDescription
+	ch <- interfaceNeighborsMetricDescription
+	ch <- interfaceAdjacenciesMetricDescription
+	ch <- lsaCountMetricDescription
+}
+
+// Supported - RouterOS v6 ships OSPF in the routing package
+func (c *ospfCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MajorVersion() != 6 || caps.MayHavePackage("routing")
+}
+
+func (c *ospfCollector) Collect(ctx *context.Context) error {
+	var (
+		interfaces []*proto.Sentence
+		neighbors  []*proto.Sentence
+	)
+
+	eg := errgroup.Group{}
+	eg.Go(func() error {
+		var err error
+		if interfaces, err = c.fetchInterfaces(ctx); err != nil {
+			return fmt.Errorf("failed to fetch ospf interfaces: %w", err)
+		}
+
+		return nil
+	})
+
+	eg.Go(func() error {
+		var err error
+		if neighbors, err = c.fetch(ctx, "/routing/ospf/neighbor/print", neighborProperties); err != nil {
+			return fmt.Errorf("failed to fetch ospf neighbors: %w", err)
+		}
+
+		return nil
+	})
+
+	eg.Go(func() error {
+		lsas, err := c.fetch(ctx, "/routing/ospf/lsa/print", lsaProperties)
+		if err != nil {
+			return fmt.Errorf("failed to fetch ospf lsa: %w", err)
+		}
+
+		c.collectLSACounts(lsas, ctx)
+
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	neighborCounts := countNeighbors(neighbors)
+	for _, re := range interfaces {
+		c.collectInterface(re, neighborCounts[re.Map["interface"]], ctx)
+	}
+
+	return nil
+}
+
+func (c *ospfCollector) fetch(ctx *context.Context, command string, properties []string) ([]*proto.Sentence, error) {
+	reply, err := ctx.RouterOSClient.Run(
+		command,
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.Re, nil
+}
+
+// fetchInterfaces - fetches interfaces, RouterOS v6 properties are renamed to the v7 ones
+func (c *ospfCollector) fetchInterfaces(ctx *context.Context) ([]*proto.Sentence, error) {
+	if ctx.Capabilities.MajorVersion() != 6 {
+		return c.fetch(ctx, "/routing/ospf/interface/print", interfaceProperties)
+	}
+
+	stats, err := c.fetch(ctx, "/routing/ospf/interface/print", v6InterfacePropertyNames)
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := make([]*proto.Sentence, 0, len(stats))
+	for _, re := range stats {
+		iface := &proto.Sentence{Map: make(map[string]string, len(v6InterfaceProperties))}
+		for p, v7Property := range v6InterfaceProperties {
+			if v, ok := re.Map[p]; ok {
+				iface.Map[v7Property] = v
+			}
+		}
+
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, nil
+}
+
+// countNeighbors - counts neighbors by interface, neighbors without interface property aren't counted
+func countNeighbors(neighbors []*proto.Sentence) map[string]*neighborCount {
+	counts := make(map[string]*neighborCount)
+	for _, re := range neighbors {
+		iface := re.Map["interface"]
+		if len(iface) == 0 {
+			continue
+		}
+
+		nc, ok := counts[iface]
+		if !ok {
+			nc = &neighborCount{}
+			counts[iface] = nc
+		}
+
+		nc.neighbors++
+		if strings.EqualFold(re.Map["state"], "full") {
+			nc.adjacencies++
+		}
+	}
+
+	return counts
+}
+
+func (c *ospfCollector) collectInterface(re *proto.Sentence, nc *neighborCount, ctx *context.Context) {
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, re.Map["instance"], re.Map["area"], re.Map["interface"]}
+
+	for p, desc := range interfaceNumericDescriptions {
+		value := re.Map[p]
+		if len(value) == 0 {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"interface": re.Map["interface"],
+				"property":  p,
+				"value":     value,
+				"error":     err,
+			}).Error("failed to parse ospf interface metric value")
+			continue
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labelValues...)
+	}
+
+	var up float64
+	if state := re.Map["state"]; len(state) != 0 && !strings.EqualFold(state, "down") {
+		up = 1
+	}
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(interfaceUpMetricDescription, prometheus.GaugeValue, up, labelValues...)
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(interfaceInfoMetricDescription, prometheus.GaugeValue, 1,
+		append(labelValues, re.Map["state"], re.Map["network-type"], re.Map["dr"], re.Map["bdr"])...,
+	)
+
+	if nc == nil {
+		nc = &neighborCount{}
+	}
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(interfaceNeighborsMetricDescription, prometheus.GaugeValue, nc.neighbors, labelValues...)
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(interfaceAdjacenciesMetricDescription, prometheus.GaugeValue, nc.adjacencies, labelValues...)
+}
+
+func (c *ospfCollector) collectLSACounts(lsas []*proto.Sentence, ctx *context.Context) {
+	type key struct {
+		instance, area, lsaType string
+	}
+
+	counts := make(map[key]float64)
+	for _, re := range lsas {
+		counts[key{instance: re.Map["instance"], area: re.Map["area"], lsaType: re.Map["type"]}]++
+	}
+
+	for k, v := range counts {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(lsaCountMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, k.instance, k.area, k.lsaType,
+		)
+	}
+}
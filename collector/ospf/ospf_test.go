@@ -0,0 +1,264 @@
+package ospf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_ospfCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("ospf", c.Name())
+}
+
+func Test_ospfCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "interface_cost", "ospf interface cost", interfaceLabelNames),
+		metrics.BuildMetricDescription(prefix, "interface_priority", "ospf interface router priority", interfaceLabelNames),
+		metrics.BuildMetricDescription(prefix, "interface_up", "ospf interface is not in down state (up = 1)", interfaceLabelNames),
+		metrics.BuildMetricDescription(prefix, "interface_info", "ospf interface state, network type and designated routers",
+			[]string{"name", "address", "instance", "area", "interface", "state", "network_type", "dr", "bdr"},
+		),
+		metrics.BuildMetricDescription(prefix, "interface_neighbors", "number of ospf neighbors on interface", interfaceLabelNames),
+		metrics.BuildMetricDescription(prefix, "interface_adjacencies", "number of ospf neighbors in full state on interface", interfaceLabelNames),
+		metrics.BuildMetricDescription(prefix, "lsa_count", "number of ospf lsa in the database by area and type",
+			[]string{"name", "address", "instance", "area", "type"},
+		),
+	}, got)
+}
+
+func Test_ospfCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Version: "7.12.1 (stable)", Packages: []string{"routeros"}}))
+	r.True(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system", "routing"}}))
+	r.False(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system"}}))
+}
+
+func Test_ospfCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	neighborSentence := []string{"/routing/ospf/neighbor/print", "=.proplist=interface,state"}
+	lsaSentence := []string{"/routing/ospf/lsa/print", "=.proplist=instance,area,type"}
+	infoDesc := metrics.BuildMetricDescription(prefix, "interface_info", "ospf interface state, network type and designated routers",
+		[]string{"name", "address", "instance", "area", "interface", "state", "network_type", "dr", "bdr"},
+	)
+	costDesc := metrics.BuildMetricDescription(prefix, "interface_cost", "ospf interface cost", interfaceLabelNames)
+	priorityDesc := metrics.BuildMetricDescription(prefix, "interface_priority", "ospf interface router priority", interfaceLabelNames)
+	upDesc := metrics.BuildMetricDescription(prefix, "interface_up", "ospf interface is not in down state (up = 1)", interfaceLabelNames)
+	neighborsDesc := metrics.BuildMetricDescription(prefix, "interface_neighbors", "number of ospf neighbors on interface", interfaceLabelNames)
+	adjacenciesDesc := metrics.BuildMetricDescription(prefix, "interface_adjacencies", "number of ospf neighbors in full state on interface", interfaceLabelNames)
+	lsaDesc := metrics.BuildMetricDescription(prefix, "lsa_count", "number of ospf lsa in the database by area and type",
+		[]string{"name", "address", "instance", "area", "type"},
+	)
+
+	testCases := []struct {
+		name         string
+		capabilities *context.Capabilities
+		setMocks     func()
+		want         []prometheus.Metric
+		errWant      string
+	}{
+		{
+			name:         "success",
+			capabilities: &context.Capabilities{Version: "7.12.1 (stable)"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/routing/ospf/interface/print",
+					"=.proplist=instance,area,interface,state,cost,priority,network-type,dr,bdr",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"instance":     "default-v2",
+								"area":         "backbone",
+								"interface":    "ether1",
+								"state":        "dr",
+								"cost":         "10",
+								"priority":     "128",
+								"network-type": "broadcast",
+								"dr":           "10.0.0.1",
+								"bdr":          "10.0.0.2",
+							},
+						},
+						{
+							Map: map[string]string{
+								"instance":     "default-v2",
+								"area":         "backbone",
+								"interface":    "ether2",
+								"state":        "down",
+								"cost":         "x",
+								"network-type": "ptp",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When(neighborSentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"interface": "ether1", "state": "Full"}},
+						{Map: map[string]string{"interface": "ether1", "state": "2-Way"}},
+						{Map: map[string]string{"state": "Full"}},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When(lsaSentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"instance": "default-v2", "area": "backbone", "type": "router"}},
+						{Map: map[string]string{"instance": "default-v2", "area": "backbone", "type": "router"}},
+						{Map: map[string]string{"instance": "default-v2", "area": "backbone", "type": "network"}},
+						{Map: map[string]string{"instance": "default-v2", "area": "external", "type": "external"}},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(costDesc, prometheus.GaugeValue, 10, "device", "address", "default-v2", "backbone", "ether1"),
+				prometheus.MustNewConstMetric(priorityDesc, prometheus.GaugeValue, 128, "device", "address", "default-v2", "backbone", "ether1"),
+				prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1, "device", "address", "default-v2", "backbone", "ether1"),
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "default-v2", "backbone", "ether1", "dr", "broadcast", "10.0.0.1", "10.0.0.2",
+				),
+				prometheus.MustNewConstMetric(neighborsDesc, prometheus.GaugeValue, 2, "device", "address", "default-v2", "backbone", "ether1"),
+				prometheus.MustNewConstMetric(adjacenciesDesc, prometheus.GaugeValue, 1, "device", "address", "default-v2", "backbone", "ether1"),
+				prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0, "device", "address", "default-v2", "backbone", "ether2"),
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "default-v2", "backbone", "ether2", "down", "ptp", "", "",
+				),
+				prometheus.MustNewConstMetric(neighborsDesc, prometheus.GaugeValue, 0, "device", "address", "default-v2", "backbone", "ether2"),
+				prometheus.MustNewConstMetric(adjacenciesDesc, prometheus.GaugeValue, 0, "device", "address", "default-v2", "backbone", "ether2"),
+				prometheus.MustNewConstMetric(lsaDesc, prometheus.GaugeValue, 2, "device", "address", "default-v2", "backbone", "router"),
+				prometheus.MustNewConstMetric(lsaDesc, prometheus.GaugeValue, 1, "device", "address", "default-v2", "backbone", "network"),
+				prometheus.MustNewConstMetric(lsaDesc, prometheus.GaugeValue, 1, "device", "address", "default-v2", "external", "external"),
+			},
+		},
+		{
+			name:         "success v6",
+			capabilities: &context.Capabilities{Version: "6.49.10 (long-term)"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/routing/ospf/interface/print",
+					"=.proplist=interface,state,cost,priority,network-type,designated-router,backup-designated-router",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"interface":                "ether1",
+								"state":                    "backup",
+								"cost":                     "10",
+								"priority":                 "1",
+								"network-type":             "broadcast",
+								"designated-router":        "10.0.0.1",
+								"backup-designated-router": "10.0.0.2",
+							},
+						},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When(neighborSentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"interface": "ether1", "state": "Full"}},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When(lsaSentence...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"instance": "default", "area": "backbone", "type": "router"}},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(costDesc, prometheus.GaugeValue, 10, "device", "address", "", "", "ether1"),
+				prometheus.MustNewConstMetric(priorityDesc, prometheus.GaugeValue, 1, "device", "address", "", "", "ether1"),
+				prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1, "device", "address", "", "", "ether1"),
+				prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1,
+					"device", "address", "", "", "ether1", "backup", "broadcast", "10.0.0.1", "10.0.0.2",
+				),
+				prometheus.MustNewConstMetric(neighborsDesc, prometheus.GaugeValue, 1, "device", "address", "", "", "ether1"),
+				prometheus.MustNewConstMetric(adjacenciesDesc, prometheus.GaugeValue, 1, "device", "address", "", "", "ether1"),
+				prometheus.MustNewConstMetric(lsaDesc, prometheus.GaugeValue, 1, "device", "address", "default", "backbone", "router"),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/routing/ospf/interface/print",
+					"=.proplist=instance,area,interface,state,cost,priority,network-type,dr,bdr",
+				}...).Then(nil, errors.New("some fetch error"))
+				routerOSClientMock.RunMock.When(neighborSentence...).Then(&routeros.Reply{}, nil)
+				routerOSClientMock.RunMock.When(lsaSentence...).Then(&routeros.Reply{}, nil)
+			},
+			errWant: "failed to fetch ospf interfaces: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+				Capabilities:   tc.capabilities,
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
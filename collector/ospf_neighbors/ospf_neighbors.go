@@ -36,6 +36,11 @@ func (c *ospfNeighborsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- metricDescription
 }
 
+// Supported - RouterOS v6 ships OSPF in the routing package
+func (c *ospfNeighborsCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MajorVersion() != 6 || caps.MayHavePackage("routing")
+}
+
 func (c *ospfNeighborsCollector) Collect(ctx *context.Context) error {
 	stats, err := c.fetch(ctx)
 	if err != nil {
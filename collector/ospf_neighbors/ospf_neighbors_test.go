@@ -48,6 +48,17 @@ func Test_ospfNeighborsCollector_Describe(t *testing.T) {
 	}, got)
 }
 
+func Test_ospfNeighborsCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Version: "7.12.1 (stable)", Packages: []string{"routeros"}}))
+	r.True(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system", "routing"}}))
+	r.False(c.Supported(&context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system"}}))
+}
+
 func Test_ospfNeighborsCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
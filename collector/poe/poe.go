@@ -10,6 +10,7 @@ import (
 	"gopkg.in/routeros.v2/proto"
 
 	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/metrics"
 )
 
@@ -23,12 +24,32 @@ var (
 	}
 )
 
-const prefix = "poe"
+const (
+	prefix        = "poe"
+	interfaceType = "ether"
+)
+
+type poeCollector struct {
+	filter *filter.Filter
+}
 
-type poeCollector struct{}
+// Option - represents a function on poe collector instance
+type Option func(*poeCollector)
 
-func NewCollector() *poeCollector {
-	return &poeCollector{}
+// WithFilter - limits metrics to the interfaces matching the filter
+func WithFilter(f *filter.Filter) Option {
+	return func(c *poeCollector) {
+		c.filter = f
+	}
+}
+
+func NewCollector(opts ...Option) *poeCollector {
+	c := &poeCollector{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
 }
 
 func (c *poeCollector) Name() string {
@@ -41,10 +62,15 @@ func (c *poeCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
+// Supported - PoE outputs are only available on RouterBOARD hardware
+func (c *poeCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayBeRouterBoard()
+}
+
 func (c *poeCollector) Collect(ctx *context.Context) error {
 	reply, err := ctx.RouterOSClient.Run(
 		"/interface/ethernet/poe/print",
-		"=.proplist=name",
+		"=.proplist="+strings.Join(c.filter.AppendProperties([]string{"name"}), ","),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch poe interface names: %w", err)
@@ -52,7 +78,13 @@ func (c *poeCollector) Collect(ctx *context.Context) error {
 
 	interfaces := make([]string, 0, len(reply.Re))
 	for _, re := range reply.Re {
-		interfaces = append(interfaces, re.Map["name"])
+		if c.filter.Match(re, interfaceType) {
+			interfaces = append(interfaces, re.Map["name"])
+		}
+	}
+
+	if len(interfaces) == 0 {
+		return nil
 	}
 
 	return c.collectMetricsForInterfaces(interfaces, ctx)
@@ -48,6 +48,16 @@ func Test_poeCollector_Describe(t *testing.T) {
 	}, got)
 }
 
+func Test_poeCollector_Supported(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.True(c.Supported(nil))
+	r.True(c.Supported(&context.Capabilities{Architecture: "arm64", Board: "RB5009UG+S+"}))
+	r.False(c.Supported(&context.Capabilities{Architecture: "x86_64", Board: "CHR"}))
+}
+
 func Test_poeCollector_Collect(t *testing.T) {
 	r := require.New(t)
 
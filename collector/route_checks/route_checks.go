@@ -0,0 +1,180 @@
+package route_checks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	labelNames      = []string{"name", "address", "prefix", "ip_version"}
+	routeLabelNames = []string{"name", "address", "prefix", "ip_version", "gateway", "routing_table"}
+
+	presentMetricDescription       = metrics.BuildMetricDescription(prefix, "present", "route to prefix exists (present = 1)", labelNames)
+	activeMetricDescription        = metrics.BuildMetricDescription(prefix, "active", "an active route to prefix exists (active = 1)", labelNames)
+	routeActiveMetricDescription   = metrics.BuildMetricDescription(prefix, "route_active", "route to prefix via gateway is active (active = 1)", routeLabelNames)
+	routeDistanceMetricDescription = metrics.BuildMetricDescription(prefix, "route_distance", "distance of route to prefix via gateway", routeLabelNames)
+)
+
+const (
+	prefix = "route_check"
+
+	defaultRoutingTable = "main"
+)
+
+type routeChecksCollector struct {
+	prefixes []string
+}
+
+func NewCollector(prefixes ...string) *routeChecksCollector {
+	return &routeChecksCollector{
+		prefixes: prefixes,
+	}
+}
+
+func (c *routeChecksCollector) Name() string {
+	return prefix
+}
+
+func (c *routeChecksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- presentMetricDescription
+	ch <- activeMetricDescription
+	ch <- routeActiveMetricDescription
+	ch <- routeDistanceMetricDescription
+}
+
+func (c *routeChecksCollector) Collect(ctx *context.Context) error {
+	eg := errgroup.Group{}
+	for i := range c.prefixes {
+		p := c.prefixes[i]
+		eg.Go(func() error {
+			return c.collectForPrefix(p, ctx)
+		})
+	}
+
+	return eg.Wait()
+}
+
+func (c *routeChecksCollector) collectForPrefix(dst string, ctx *context.Context) error {
+	topic, ipVersion := "ip", "4"
+	if strings.Contains(dst, ":") {
+		topic, ipVersion = "ipv6", "6"
+	}
+
+	tableProperty := "routing-table"
+	if ctx.Capabilities.MajorVersion() == 6 {
+		tableProperty = "routing-mark"
+	}
+
+	reply, err := ctx.RouterOSClient.Run(
+		fmt.Sprintf("/%s/route/print", topic),
+		"?dst-address="+dst,
+		"?disabled=false",
+		"=.proplist="+strings.Join([]string{"gateway", "distance", "active", tableProperty}, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch routes to %s: %w", dst, err)
+	}
+
+	type routeKey struct {
+		gateway string
+		table   string
+	}
+
+	// routes via the same gateway in the same table, e.g. floating static routes, share their label values,
+	// so only the preferred route is exported
+	var keys []routeKey
+	routes := make(map[routeKey]*proto.Sentence)
+
+	var present, active float64
+	for _, re := range reply.Re {
+		present = 1
+		if re.Map["active"] == "true" {
+			active = 1
+		}
+
+		table := re.Map[tableProperty]
+		if len(table) == 0 {
+			table = defaultRoutingTable
+		}
+
+		key := routeKey{gateway: re.Map["gateway"], table: table}
+		existing, ok := routes[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		if !ok || isPreferredRoute(re, existing) {
+			routes[key] = re
+		}
+	}
+
+	for _, key := range keys {
+		c.collectForRoute(dst, ipVersion, key.table, routes[key], ctx)
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(presentMetricDescription, prometheus.GaugeValue, present,
+		ctx.DeviceName, ctx.DeviceAddress, dst, ipVersion,
+	)
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(activeMetricDescription, prometheus.GaugeValue, active,
+		ctx.DeviceName, ctx.DeviceAddress, dst, ipVersion,
+	)
+
+	return nil
+}
+
+func (c *routeChecksCollector) collectForRoute(dst, ipVersion, table string, re *proto.Sentence, ctx *context.Context) {
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, dst, ipVersion, re.Map["gateway"], table}
+
+	var active float64
+	if re.Map["active"] == "true" {
+		active = 1
+	}
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(routeActiveMetricDescription, prometheus.GaugeValue, active, labelValues...)
+
+	value := re.Map["distance"]
+	if len(value) == 0 {
+		return
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector": c.Name(),
+			"device":    ctx.DeviceName,
+			"prefix":    dst,
+			"value":     value,
+			"error":     err,
+		}).Error("failed to parse route distance")
+		return
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(routeDistanceMetricDescription, prometheus.GaugeValue, v, labelValues...)
+}
+
+// isPreferredRoute - reports whether route a is preferred over route b, an active route wins over an inactive one,
+// otherwise the route with the lower distance
+func isPreferredRoute(a, b *proto.Sentence) bool {
+	if aActive, bActive := a.Map["active"] == "true", b.Map["active"] == "true"; aActive != bActive {
+		return aActive
+	}
+
+	aDistance, err := strconv.Atoi(a.Map["distance"])
+	if err != nil {
+		return false
+	}
+
+	bDistance, err := strconv.Atoi(b.Map["distance"])
+	if err != nil {
+		return true
+	}
+
+	return aDistance < bDistance
+}
@@ -0,0 +1,205 @@
+package route_checks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_routeChecksCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("route_check", c.Name())
+}
+
+func Test_routeChecksCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "present", "route to prefix exists (present = 1)", labelNames),
+		metrics.BuildMetricDescription(prefix, "active", "an active route to prefix exists (active = 1)", labelNames),
+		metrics.BuildMetricDescription(prefix, "route_active", "route to prefix via gateway is active (active = 1)", routeLabelNames),
+		metrics.BuildMetricDescription(prefix, "route_distance", "distance of route to prefix via gateway", routeLabelNames),
+	}, got)
+}
+
+func Test_routeChecksCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	testCases := []struct {
+		name         string
+		prefixes     []string
+		capabilities *context.Capabilities
+		setMocks     func()
+		want         []prometheus.Metric
+		errWant      string
+	}{
+		{
+			name:         "success",
+			prefixes:     []string{"0.0.0.0/0", "2001:db8::/32", "10.0.0.0/8"},
+			capabilities: &context.Capabilities{Version: "7.12.1 (stable)"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/route/print",
+					"?dst-address=0.0.0.0/0",
+					"?disabled=false",
+					"=.proplist=gateway,distance,active,routing-table",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"gateway": "192.168.1.1", "distance": "1", "active": "true", "routing-table": "main"}},
+						{Map: map[string]string{"gateway": "192.168.2.1", "distance": "x", "active": "false", "routing-table": "main"}},
+						{Map: map[string]string{"gateway": "192.168.1.1", "distance": "0", "active": "false", "routing-table": "main"}},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/ipv6/route/print",
+					"?dst-address=2001:db8::/32",
+					"?disabled=false",
+					"=.proplist=gateway,distance,active,routing-table",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"gateway": "fe80::1%ether1", "distance": "20", "routing-table": "customer_a"}},
+						{Map: map[string]string{"gateway": "fe80::1%ether1", "distance": "5", "routing-table": "customer_a"}},
+						{Map: map[string]string{"gateway": "fe80::1%ether1", "distance": "x", "routing-table": "customer_a"}},
+					},
+				}, nil)
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/route/print",
+					"?dst-address=10.0.0.0/8",
+					"?disabled=false",
+					"=.proplist=gateway,distance,active,routing-table",
+				}...).Then(&routeros.Reply{}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(presentMetricDescription, prometheus.GaugeValue, 1, "device", "address", "0.0.0.0/0", "4"),
+				prometheus.MustNewConstMetric(activeMetricDescription, prometheus.GaugeValue, 1, "device", "address", "0.0.0.0/0", "4"),
+				prometheus.MustNewConstMetric(routeActiveMetricDescription, prometheus.GaugeValue, 1,
+					"device", "address", "0.0.0.0/0", "4", "192.168.1.1", "main",
+				),
+				prometheus.MustNewConstMetric(routeDistanceMetricDescription, prometheus.GaugeValue, 1,
+					"device", "address", "0.0.0.0/0", "4", "192.168.1.1", "main",
+				),
+				prometheus.MustNewConstMetric(routeActiveMetricDescription, prometheus.GaugeValue, 0,
+					"device", "address", "0.0.0.0/0", "4", "192.168.2.1", "main",
+				),
+				prometheus.MustNewConstMetric(presentMetricDescription, prometheus.GaugeValue, 1, "device", "address", "2001:db8::/32", "6"),
+				prometheus.MustNewConstMetric(activeMetricDescription, prometheus.GaugeValue, 0, "device", "address", "2001:db8::/32", "6"),
+				prometheus.MustNewConstMetric(routeActiveMetricDescription, prometheus.GaugeValue, 0,
+					"device", "address", "2001:db8::/32", "6", "fe80::1%ether1", "customer_a",
+				),
+				prometheus.MustNewConstMetric(routeDistanceMetricDescription, prometheus.GaugeValue, 5,
+					"device", "address", "2001:db8::/32", "6", "fe80::1%ether1", "customer_a",
+				),
+				prometheus.MustNewConstMetric(presentMetricDescription, prometheus.GaugeValue, 0, "device", "address", "10.0.0.0/8", "4"),
+				prometheus.MustNewConstMetric(activeMetricDescription, prometheus.GaugeValue, 0, "device", "address", "10.0.0.0/8", "4"),
+			},
+		},
+		{
+			name:         "success v6",
+			prefixes:     []string{"0.0.0.0/0"},
+			capabilities: &context.Capabilities{Version: "6.49.10 (long-term)"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/route/print",
+					"?dst-address=0.0.0.0/0",
+					"?disabled=false",
+					"=.proplist=gateway,distance,active,routing-mark",
+				}...).Then(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{Map: map[string]string{"gateway": "192.168.1.1", "distance": "1", "active": "true"}},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(presentMetricDescription, prometheus.GaugeValue, 1, "device", "address", "0.0.0.0/0", "4"),
+				prometheus.MustNewConstMetric(activeMetricDescription, prometheus.GaugeValue, 1, "device", "address", "0.0.0.0/0", "4"),
+				prometheus.MustNewConstMetric(routeActiveMetricDescription, prometheus.GaugeValue, 1,
+					"device", "address", "0.0.0.0/0", "4", "192.168.1.1", "main",
+				),
+				prometheus.MustNewConstMetric(routeDistanceMetricDescription, prometheus.GaugeValue, 1,
+					"device", "address", "0.0.0.0/0", "4", "192.168.1.1", "main",
+				),
+			},
+		},
+		{
+			name:     "fetch error",
+			prefixes: []string{"0.0.0.0/0"},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/ip/route/print",
+					"?dst-address=0.0.0.0/0",
+					"?disabled=false",
+					"=.proplist=gateway,distance,active,routing-table",
+				}...).Then(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch routes to 0.0.0.0/0: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCollector(tc.prefixes...)
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+				Capabilities:   tc.capabilities,
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
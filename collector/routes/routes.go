@@ -3,6 +3,7 @@ package routes
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
@@ -13,18 +14,59 @@ import (
 )
 
 var (
-	protocols                       = []string{"bgp", "static", "ospf", "dynamic", "connect", "rip"}
+	defaultProtocols                = []string{"bgp", "static", "ospf", "dynamic", "connect", "rip"}
 	labelNames                      = []string{"name", "address", "ip_version"}
+	tableLabelNames                 = []string{"name", "address", "ip_version", "routing_table"}
 	totalRoutesMetricDescription    = metrics.BuildMetricDescription(prefix, "total", "number of routes in rib", labelNames)
 	protocolRoutesMetricDescription = metrics.BuildMetricDescription(prefix, "by_protocol", "number of routes per protocol in rib", append(labelNames, "protocol"))
+
+	tableActiveRoutesMetricDescription = metrics.BuildMetricDescription(prefix, "table_active", "number of active routes per routing table and distance",
+		append(tableLabelNames, "distance"),
+	)
+	tableInactiveRoutesMetricDescription    = metrics.BuildMetricDescription(prefix, "table_inactive", "number of inactive routes per routing table", tableLabelNames)
+	tableUnreachableRoutesMetricDescription = metrics.BuildMetricDescription(prefix, "table_unreachable", "number of unreachable routes per routing table", tableLabelNames)
+	tableProtocolRoutesMetricDescription    = metrics.BuildMetricDescription(prefix, "table_by_protocol", "number of active routes per routing table and protocol",
+		append(tableLabelNames, "protocol"),
+	)
 )
 
-const prefix = "routes"
+const (
+	prefix = "routes"
+
+	defaultRoutingTable = "main"
+)
+
+type routesCollector struct {
+	protocols []string
+	byTable   bool
+}
+
+// Option - represents a function on routes collector instance
+type Option func(*routesCollector)
+
+// WithProtocols - replaces the default protocols routes are counted by, e.g. bgp-vpn on RouterOS v7
+func WithProtocols(protocols ...string) Option {
+	return func(c *routesCollector) {
+		c.protocols = protocols
+	}
+}
+
+// WithRoutingTables - enables counts per routing table (VRF), distance and protocol, fetches all routes on every scrape
+func WithRoutingTables() Option {
+	return func(c *routesCollector) {
+		c.byTable = true
+	}
+}
 
-type routesCollector struct{}
+func NewCollector(opts ...Option) *routesCollector {
+	c := &routesCollector{
+		protocols: defaultProtocols,
+	}
+	for _, o := range opts {
+		o(c)
+	}
 
-func NewCollector() *routesCollector {
-	return &routesCollector{}
+	return c
 }
 
 func (c *routesCollector) Name() string {
@@ -34,6 +76,13 @@ func (c *routesCollector) Name() string {
 func (c *routesCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- totalRoutesMetricDescription
 	ch <- protocolRoutesMetricDescription
+
+	if c.byTable {
+		ch <- tableActiveRoutesMetricDescription
+		ch <- tableInactiveRoutesMetricDescription
+		ch <- tableUnreachableRoutesMetricDescription
+		ch <- tableProtocolRoutesMetricDescription
+	}
 }
 
 func (c *routesCollector) Collect(ctx *context.Context) error {
@@ -42,9 +91,12 @@ func (c *routesCollector) Collect(ctx *context.Context) error {
 		return c.collectForIPVersion("ip", "4", ctx)
 	})
 
-	eg.Go(func() error {
-		return c.collectForIPVersion("ipv6", "6", ctx)
-	})
+	// RouterOS v6 ships IPv6 support in a separate package, which may be disabled
+	if ctx.Capabilities.MajorVersion() != 6 || ctx.Capabilities.MayHavePackage("ipv6") {
+		eg.Go(func() error {
+			return c.collectForIPVersion("ipv6", "6", ctx)
+		})
+	}
 
 	return eg.Wait()
 }
@@ -55,13 +107,19 @@ func (c *routesCollector) collectForIPVersion(topic, ipVersion string, ctx *cont
 		return c.collectTotalCount(topic, ipVersion, ctx)
 	})
 
-	for i := range protocols {
-		p := protocols[i]
+	for i := range c.protocols {
+		p := c.protocols[i]
 		eg.Go(func() error {
 			return c.collectCountByProtocol(topic, ipVersion, p, ctx)
 		})
 	}
 
+	if c.byTable {
+		eg.Go(func() error {
+			return c.collectByTable(topic, ipVersion, ctx)
+		})
+	}
+
 	return eg.Wait()
 }
 
@@ -132,3 +190,88 @@ func (c *routesCollector) collectCountByProtocol(topic, ipVersion, protocol stri
 
 	return nil
 }
+
+// collectByTable - counts routes per routing table, RouterOS v6 routing marks are handled as tables
+func (c *routesCollector) collectByTable(topic, ipVersion string, ctx *context.Context) error {
+	tableProperty := "routing-table"
+	if ctx.Capabilities.MajorVersion() == 6 {
+		tableProperty = "routing-mark"
+	}
+
+	properties := append([]string{tableProperty, "distance", "active", "unreachable"}, c.protocols...)
+	reply, err := ctx.RouterOSClient.Run(
+		fmt.Sprintf("/%s/route/print", topic),
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch routes by table: %w", err)
+	}
+
+	type (
+		distanceKey struct {
+			table, distance string
+		}
+		protocolKey struct {
+			table, protocol string
+		}
+	)
+
+	var (
+		inactive    = make(map[string]float64)
+		unreachable = make(map[string]float64)
+		active      = make(map[distanceKey]float64)
+		byProtocol  = make(map[protocolKey]float64)
+	)
+	for _, re := range reply.Re {
+		table := re.Map[tableProperty]
+		if len(table) == 0 {
+			table = defaultRoutingTable
+		}
+
+		// make sure every table is exported, even without inactive or unreachable routes
+		inactive[table] += 0
+		unreachable[table] += 0
+
+		if re.Map["unreachable"] == "true" {
+			unreachable[table]++
+		}
+
+		if re.Map["active"] != "true" {
+			inactive[table]++
+			continue
+		}
+
+		active[distanceKey{table: table, distance: re.Map["distance"]}]++
+		for _, p := range c.protocols {
+			if re.Map[p] == "true" {
+				byProtocol[protocolKey{table: table, protocol: p}]++
+			}
+		}
+	}
+
+	for table, v := range inactive {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(tableInactiveRoutesMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, ipVersion, table,
+		)
+	}
+
+	for table, v := range unreachable {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(tableUnreachableRoutesMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, ipVersion, table,
+		)
+	}
+
+	for k, v := range active {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(tableActiveRoutesMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, ipVersion, k.table, k.distance,
+		)
+	}
+
+	for k, v := range byProtocol {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(tableProtocolRoutesMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, ipVersion, k.table, k.protocol,
+		)
+	}
+
+	return nil
+}
@@ -528,3 +528,139 @@ func Test_routesCollector_Collect(t *testing.T) {
 		})
 	}
 }
+
+func Test_routesCollector_CollectByTable(t *testing.T) {
+	r := require.New(t)
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	defer routerOSClientMock.MinimockFinish()
+	routerOSClientMock.RunMock.Set(func(sentence ...string) (*routeros.Reply, error) {
+		if sentence[len(sentence)-1] == "=count-only=" {
+			return &routeros.Reply{
+				Done: &proto.Sentence{
+					Map: map[string]string{"ret": "1"},
+				},
+			}, nil
+		}
+
+		switch sentence[0] {
+		case "/ip/route/print":
+			r.Equal([]string{"/ip/route/print", "=.proplist=routing-table,distance,active,unreachable,bgp,bgp-vpn"}, sentence)
+			return &routeros.Reply{
+				Re: []*proto.Sentence{
+					{Map: map[string]string{"routing-table": "main", "distance": "1", "active": "true"}},
+					{Map: map[string]string{"routing-table": "main", "distance": "20", "active": "true", "bgp": "true"}},
+					{Map: map[string]string{"routing-table": "main", "distance": "20", "active": "true", "bgp": "true"}},
+					{Map: map[string]string{"routing-table": "main", "distance": "200", "bgp": "true"}},
+					{Map: map[string]string{"routing-table": "customer_a", "distance": "200", "active": "true", "bgp-vpn": "true"}},
+					{Map: map[string]string{"routing-table": "customer_a", "distance": "1", "unreachable": "true"}},
+				},
+			}, nil
+		case "/ipv6/route/print":
+			return &routeros.Reply{}, nil
+		default:
+			r.FailNow("unexpected command")
+			return nil, nil
+		}
+	})
+
+	c := NewCollector(WithProtocols("bgp", "bgp-vpn"), WithRoutingTables())
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	var got []prometheus.Metric
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	err := c.Collect(&context.Context{
+		RouterOSClient: routerOSClientMock,
+		MetricsChan:    ch,
+		DeviceName:     "device",
+		DeviceAddress:  "address",
+		Capabilities:   &context.Capabilities{Version: "7.12.1 (stable)"},
+	})
+	close(ch)
+	r.NoError(err)
+
+	<-done
+	r.ElementsMatch([]prometheus.Metric{
+		prometheus.MustNewConstMetric(totalRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4"),
+		prometheus.MustNewConstMetric(totalRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "6"),
+		prometheus.MustNewConstMetric(protocolRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4", "bgp"),
+		prometheus.MustNewConstMetric(protocolRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4", "bgp-vpn"),
+		prometheus.MustNewConstMetric(protocolRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "6", "bgp"),
+		prometheus.MustNewConstMetric(protocolRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "6", "bgp-vpn"),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "table_active", "number of active routes per routing table and distance",
+				[]string{"name", "address", "ip_version", "routing_table", "distance"},
+			),
+			prometheus.GaugeValue, 1, "device", "address", "4", "main", "1",
+		),
+		prometheus.MustNewConstMetric(tableActiveRoutesMetricDescription, prometheus.GaugeValue, 2, "device", "address", "4", "main", "20"),
+		prometheus.MustNewConstMetric(tableActiveRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4", "customer_a", "200"),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "table_inactive", "number of inactive routes per routing table", tableLabelNames),
+			prometheus.GaugeValue, 1, "device", "address", "4", "main",
+		),
+		prometheus.MustNewConstMetric(tableInactiveRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4", "customer_a"),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "table_unreachable", "number of unreachable routes per routing table", tableLabelNames),
+			prometheus.GaugeValue, 0, "device", "address", "4", "main",
+		),
+		prometheus.MustNewConstMetric(tableUnreachableRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4", "customer_a"),
+		prometheus.MustNewConstMetric(
+			metrics.BuildMetricDescription(prefix, "table_by_protocol", "number of active routes per routing table and protocol",
+				[]string{"name", "address", "ip_version", "routing_table", "protocol"},
+			),
+			prometheus.GaugeValue, 2, "device", "address", "4", "main", "bgp",
+		),
+		prometheus.MustNewConstMetric(tableProtocolRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4", "customer_a", "bgp-vpn"),
+	}, got)
+}
+
+func Test_routesCollector_CollectWithoutIPv6Package(t *testing.T) {
+	r := require.New(t)
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	defer routerOSClientMock.MinimockFinish()
+	routerOSClientMock.RunMock.Set(func(sentence ...string) (*routeros.Reply, error) {
+		r.Equal("/ip/route/print", sentence[0])
+		return &routeros.Reply{
+			Done: &proto.Sentence{
+				Map: map[string]string{"ret": "1"},
+			},
+		}, nil
+	})
+
+	c := NewCollector(WithProtocols("bgp"))
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	var got []prometheus.Metric
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	err := c.Collect(&context.Context{
+		RouterOSClient: routerOSClientMock,
+		MetricsChan:    ch,
+		DeviceName:     "device",
+		DeviceAddress:  "address",
+		Capabilities:   &context.Capabilities{Version: "6.49.10 (long-term)", Packages: []string{"system", "routing"}},
+	})
+	close(ch)
+	r.NoError(err)
+
+	<-done
+	r.ElementsMatch([]prometheus.Metric{
+		prometheus.MustNewConstMetric(totalRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4"),
+		prometheus.MustNewConstMetric(protocolRoutesMetricDescription, prometheus.GaugeValue, 1, "device", "address", "4", "bgp"),
+	}, got)
+}
@@ -0,0 +1,279 @@
+package script
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/routeros.v2"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+// Output formats supported for script output
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+const defaultTimeout = 5 * time.Second
+
+var (
+	lineRegexp  = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)(?:\{(.*)\})?\s+(\S+)$`)
+	labelRegexp = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)="((?:[^"\\]|\\.)*)"\s*(?:,|$)`)
+
+	errTimeout = errors.New("script timed out")
+)
+
+type (
+	// Definition - describes a script collector
+	Definition struct {
+		// Name - collector name, used as metric prefix
+		Name string
+		// Source - inline script source, either Source or ScriptName is required
+		Source string
+		// ScriptName - name of a script from /system/script, either Source or ScriptName is required
+		ScriptName string
+		// Format - text or json, optional, defaults to text
+		Format string
+		// Timeout - max script execution time, optional, defaults to 5s
+		Timeout time.Duration
+		// AllowExecute - confirms that the script may be executed on the device
+		AllowExecute bool
+	}
+
+	// sample - represents a single value parsed from script output
+	sample struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+		Value  float64           `json:"value"`
+	}
+
+	scriptCollector struct {
+		name    string
+		script  string
+		format  string
+		timeout time.Duration
+	}
+)
+
+// NewCollector - script collector constructor, validates the definition
+func NewCollector(def *Definition) (*scriptCollector, error) {
+	if len(def.Name) == 0 {
+		return nil, fmt.Errorf("script collector name is required")
+	}
+
+	if !def.AllowExecute {
+		return nil, fmt.Errorf("script collector %s: allow_execute has to be set to run scripts on devices", def.Name)
+	}
+
+	c := &scriptCollector{
+		name:    def.Name,
+		format:  def.Format,
+		timeout: def.Timeout,
+	}
+
+	switch {
+	case len(def.Source) != 0 && len(def.ScriptName) != 0:
+		return nil, fmt.Errorf("script collector %s: source and script name are mutually exclusive", def.Name)
+	case len(def.Source) != 0:
+		c.script = def.Source
+	case len(def.ScriptName) != 0:
+		c.script = fmt.Sprintf("/system script run %q", def.ScriptName)
+	default:
+		return nil, fmt.Errorf("script collector %s: source or script name is required", def.Name)
+	}
+
+	switch c.format {
+	case "":
+		c.format = FormatText
+	case FormatText, FormatJSON:
+	default:
+		return nil, fmt.Errorf("script collector %s: unknown format %q", def.Name, def.Format)
+	}
+
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+
+	return c, nil
+}
+
+func (c *scriptCollector) Name() string {
+	return c.name
+}
+
+// Describe - metrics are only known after running the script, so the collector stays unchecked
+func (c *scriptCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c *scriptCollector) Collect(ctx *context.Context) error {
+	output, err := c.execute(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to execute script: %w", err)
+	}
+
+	var samples []*sample
+	switch c.format {
+	case FormatJSON:
+		samples, err = parseJSON(output)
+	default:
+		samples, err = c.parseText(output, ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse script output: %w", err)
+	}
+
+	for _, s := range samples {
+		c.collectSample(s, ctx)
+	}
+
+	return nil
+}
+
+// execute - runs the script on a dedicated connection, which is closed on return, so a script hitting the timeout
+// neither blocks the device connection shared by the other collectors nor keeps the waiting goroutine around
+func (c *scriptCollector) execute(ctx *context.Context) (string, error) {
+	type result struct {
+		reply *routeros.Reply
+		err   error
+	}
+
+	cl, err := ctx.NewRouterOSClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to create client: %w", err)
+	}
+	defer cl.Close()
+
+	// buffered, so the goroutine can return after the timeout is hit first
+	res := make(chan result, 1)
+	go func() {
+		reply, err := cl.Run(
+			"/execute",
+			"=script="+c.script,
+			"=as-string=",
+		)
+		res <- result{reply: reply, err: err}
+	}()
+
+	select {
+	case r := <-res:
+		if r.err != nil {
+			return "", r.err
+		}
+		if r.reply.Done == nil {
+			return "", nil
+		}
+		return r.reply.Done.Map["ret"], nil
+	case <-time.After(c.timeout):
+		return "", errTimeout
+	}
+}
+
+func (c *scriptCollector) parseText(output string, ctx *context.Context) ([]*sample, error) {
+	var samples []*sample
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		s, err := parseLine(line)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"collector": c.Name(),
+				"device":    ctx.DeviceName,
+				"line":      line,
+				"error":     err,
+			}).Error("failed to parse script output line")
+			continue
+		}
+
+		samples = append(samples, s)
+	}
+
+	return samples, nil
+}
+
+func parseLine(line string) (*sample, error) {
+	matches := lineRegexp.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("expected format metric{label=\"value\"} value")
+	}
+
+	v, err := strconv.ParseFloat(matches[3], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sample{
+		Name:   matches[1],
+		Labels: make(map[string]string),
+		Value:  v,
+	}
+
+	labels := matches[2]
+	for len(strings.TrimSpace(labels)) != 0 {
+		lm := labelRegexp.FindStringSubmatch(labels)
+		if lm == nil {
+			return nil, fmt.Errorf("invalid labels %q", matches[2])
+		}
+
+		value, err := strconv.Unquote(`"` + lm[2] + `"`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label value %q: %w", lm[2], err)
+		}
+
+		s.Labels[lm[1]] = value
+		labels = labels[len(lm[0]):]
+	}
+
+	return s, nil
+}
+
+func parseJSON(output string) ([]*sample, error) {
+	var samples []*sample
+	if err := json.Unmarshal([]byte(output), &samples); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+func (c *scriptCollector) collectSample(s *sample, ctx *context.Context) {
+	labelNames := make([]string, 0, len(s.Labels)+2)
+	labelNames = append(labelNames, "name", "address")
+	names := make([]string, 0, len(s.Labels))
+	for l := range s.Labels {
+		names = append(names, l)
+	}
+	sort.Strings(names)
+
+	labelValues := make([]string, 0, len(labelNames))
+	labelValues = append(labelValues, ctx.DeviceName, ctx.DeviceAddress)
+	for _, l := range names {
+		labelNames = append(labelNames, l)
+		labelValues = append(labelValues, s.Labels[l])
+	}
+
+	desc := metrics.BuildMetricDescription(c.name, s.Name, fmt.Sprintf("%s from script collector %s", s.Name, c.name), labelNames)
+	metric, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, s.Value, labelValues...)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"collector": c.Name(),
+			"device":    ctx.DeviceName,
+			"metric":    s.Name,
+			"error":     err,
+		}).Error("failed to build script metric")
+		return
+	}
+
+	ctx.MetricsChan <- metric
+}
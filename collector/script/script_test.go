@@ -0,0 +1,271 @@
+package script
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	ros "github.com/psolru/mikrotik-exporter/routeros"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func TestNewCollector(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		name    string
+		def     *Definition
+		errWant string
+	}{
+		{
+			name: "valid inline script",
+			def:  &Definition{Name: "wifi", Source: ":put 1", AllowExecute: true},
+		},
+		{
+			name: "valid named script",
+			def:  &Definition{Name: "wifi", ScriptName: "wifi-stats", Format: FormatJSON, AllowExecute: true},
+		},
+		{
+			name:    "missing name",
+			def:     &Definition{Source: ":put 1", AllowExecute: true},
+			errWant: "script collector name is required",
+		},
+		{
+			name:    "execution not allowed",
+			def:     &Definition{Name: "wifi", Source: ":put 1"},
+			errWant: "script collector wifi: allow_execute has to be set to run scripts on devices",
+		},
+		{
+			name:    "missing script",
+			def:     &Definition{Name: "wifi", AllowExecute: true},
+			errWant: "script collector wifi: source or script name is required",
+		},
+		{
+			name:    "source and script name",
+			def:     &Definition{Name: "wifi", Source: ":put 1", ScriptName: "wifi-stats", AllowExecute: true},
+			errWant: "script collector wifi: source and script name are mutually exclusive",
+		},
+		{
+			name:    "unknown format",
+			def:     &Definition{Name: "wifi", Source: ":put 1", Format: "yaml", AllowExecute: true},
+			errWant: "script collector wifi: unknown format \"yaml\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewCollector(tc.def)
+			if len(tc.errWant) != 0 {
+				r.EqualError(err, tc.errWant)
+			} else {
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func Test_scriptCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c, err := NewCollector(&Definition{Name: "wifi", Source: ":put 1", AllowExecute: true})
+	r.NoError(err)
+
+	r.Equal("wifi", c.Name())
+}
+
+func Test_scriptCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	clientsDesc := metrics.BuildMetricDescription("wifi", "clients", "clients from script collector wifi",
+		[]string{"name", "address", "band", "interface"},
+	)
+	totalDesc := metrics.BuildMetricDescription("wifi", "total", "total from script collector wifi",
+		[]string{"name", "address"},
+	)
+
+	testCases := []struct {
+		name     string
+		def      *Definition
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success text",
+			def:  &Definition{Name: "wifi", Source: ":put \"total 3\"", AllowExecute: true},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/execute",
+					"=script=:put \"total 3\"",
+					"=as-string=",
+				}...).Then(&routeros.Reply{
+					Done: &proto.Sentence{
+						Map: map[string]string{
+							"ret": "# comment\n" +
+								"clients{interface=\"wlan1\",band=\"2ghz\"} 2\r\n" +
+								"clients{interface=\"wlan2\", band=\"5\\\"ghz\"} 1\n" +
+								"total 3\n" +
+								"broken{interface=wlan1} 1\n" +
+								"invalid value\n",
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(clientsDesc, prometheus.GaugeValue, 2, "device", "address", "2ghz", "wlan1"),
+				prometheus.MustNewConstMetric(clientsDesc, prometheus.GaugeValue, 1, "device", "address", "5\"ghz", "wlan2"),
+				prometheus.MustNewConstMetric(totalDesc, prometheus.GaugeValue, 3, "device", "address"),
+			},
+		},
+		{
+			name: "success json",
+			def:  &Definition{Name: "wifi", ScriptName: "wifi-stats", Format: FormatJSON, AllowExecute: true},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/execute",
+					"=script=/system script run \"wifi-stats\"",
+					"=as-string=",
+				}...).Then(&routeros.Reply{
+					Done: &proto.Sentence{
+						Map: map[string]string{
+							"ret": `[{"name":"clients","labels":{"interface":"wlan1","band":"2ghz"},"value":2},{"name":"total","value":2}]`,
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(clientsDesc, prometheus.GaugeValue, 2, "device", "address", "2ghz", "wlan1"),
+				prometheus.MustNewConstMetric(totalDesc, prometheus.GaugeValue, 2, "device", "address"),
+			},
+		},
+		{
+			name: "invalid json",
+			def:  &Definition{Name: "wifi", ScriptName: "wifi-stats", Format: FormatJSON, AllowExecute: true},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/execute",
+					"=script=/system script run \"wifi-stats\"",
+					"=as-string=",
+				}...).Then(&routeros.Reply{
+					Done: &proto.Sentence{
+						Map: map[string]string{"ret": "total 2"},
+					},
+				}, nil)
+			},
+			errWant: "failed to parse script output: invalid character 'o' in literal true (expecting 'r')",
+		},
+		{
+			name: "execute error",
+			def:  &Definition{Name: "wifi", Source: ":put 1", AllowExecute: true},
+			setMocks: func() {
+				routerOSClientMock.RunMock.When([]string{
+					"/execute",
+					"=script=:put 1",
+					"=as-string=",
+				}...).Then(nil, errors.New("some execute error"))
+			},
+			errWant: "failed to execute script: some execute error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewCollector(tc.def)
+			r.NoError(err)
+			resetMocks()
+			tc.setMocks()
+			routerOSClientMock.CloseMock.Return()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				NewRouterOSClient: func() (ros.Client, error) {
+					return routerOSClientMock, nil
+				},
+				MetricsChan:   ch,
+				DeviceName:    "device",
+				DeviceAddress: "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
+
+func Test_scriptCollector_CollectTimeout(t *testing.T) {
+	r := require.New(t)
+
+	closed := make(chan struct{})
+	routerOSClientMock := mocks.NewClientMock(t)
+	defer routerOSClientMock.MinimockFinish()
+	routerOSClientMock.RunMock.Set(func(sentence ...string) (*routeros.Reply, error) {
+		<-closed
+		return nil, errors.New("use of closed network connection")
+	})
+	routerOSClientMock.CloseMock.Set(func() {
+		close(closed)
+	})
+
+	c, err := NewCollector(&Definition{Name: "wifi", Source: ":delay 1s", Timeout: 10 * time.Millisecond, AllowExecute: true})
+	r.NoError(err)
+
+	err = c.Collect(&context.Context{
+		NewRouterOSClient: func() (ros.Client, error) {
+			return routerOSClientMock, nil
+		},
+		MetricsChan:   make(chan prometheus.Metric),
+		DeviceName:    "device",
+		DeviceAddress: "address",
+	})
+	r.EqualError(err, "failed to execute script: script timed out")
+
+	// closing the dedicated connection makes the pending call return
+	r.Eventually(func() bool {
+		return routerOSClientMock.RunAfterCounter() == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_scriptCollector_CollectClientError(t *testing.T) {
+	r := require.New(t)
+
+	c, err := NewCollector(&Definition{Name: "wifi", Source: ":put 1", AllowExecute: true})
+	r.NoError(err)
+
+	err = c.Collect(&context.Context{
+		NewRouterOSClient: func() (ros.Client, error) {
+			return nil, errors.New("some dial error")
+		},
+		MetricsChan:   make(chan prometheus.Metric),
+		DeviceName:    "device",
+		DeviceAddress: "address",
+	})
+	r.EqualError(err, "failed to execute script: failed to create client: some dial error")
+}
@@ -0,0 +1,119 @@
+package vrrp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+var (
+	properties         = []string{"name", "interface", "vrid", "priority", "master", "backup", "running"}
+	states             = []string{"master", "backup", "init"}
+	labelNames         = []string{"name", "address", "vrrp", "interface", "vrid", "priority"}
+	metricDescriptions = map[string]*metrics.MetricDescription{
+		"state": {
+			Desc:      metrics.BuildMetricDescription(prefix, "state", "vrrp instance state (current state = 1)", append(labelNames, "state")),
+			ValueType: prometheus.GaugeValue,
+		},
+		"running": {
+			Desc:      metrics.BuildMetricDescription(prefix, "running", "vrrp instance running (running = 1)", labelNames),
+			ValueType: prometheus.GaugeValue,
+		},
+	}
+)
+
+const prefix = "vrrp"
+
+type vrrpCollector struct{}
+
+func NewCollector() *vrrpCollector {
+	return &vrrpCollector{}
+}
+
+func (c *vrrpCollector) Name() string {
+	return prefix
+}
+
+func (c *vrrpCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range metricDescriptions {
+		ch <- d.Desc
+	}
+}
+
+func (c *vrrpCollector) Collect(ctx *context.Context) error {
+	stats, err := c.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch vrrp metrics: %w", err)
+	}
+
+	for _, re := range stats {
+		c.collectForStat(re, ctx)
+	}
+
+	return nil
+}
+
+func (c *vrrpCollector) fetch(ctx *context.Context) ([]*proto.Sentence, error) {
+	reply, err := ctx.RouterOSClient.Run(
+		"/interface/vrrp/print",
+		"?disabled=false",
+		"=.proplist="+strings.Join(properties, ","),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.Re, nil
+}
+
+func (c *vrrpCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
+	for p := range metricDescriptions {
+		c.collectMetricForProperty(p, re, ctx)
+	}
+}
+
+func (c *vrrpCollector) collectMetricForProperty(property string, re *proto.Sentence, ctx *context.Context) {
+	desc := metricDescriptions[property]
+	labelValues := []string{ctx.DeviceName, ctx.DeviceAddress, re.Map["name"], re.Map["interface"], re.Map["vrid"], re.Map["priority"]}
+
+	switch property {
+	case "state":
+		current := stateOf(re)
+		for _, s := range states {
+			var v float64
+			if s == current {
+				v = 1
+			}
+
+			ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, v, append(labelValues, s)...)
+		}
+	default:
+		value := re.Map[property]
+		if len(value) == 0 {
+			return
+		}
+
+		var v float64
+		if value == "true" {
+			v = 1
+		}
+
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(desc.Desc, desc.ValueType, v, labelValues...)
+	}
+}
+
+func stateOf(re *proto.Sentence) string {
+	switch {
+	case re.Map["master"] == "true":
+		return "master"
+	case re.Map["backup"] == "true":
+		return "backup"
+	default:
+		return "init"
+	}
+}
@@ -0,0 +1,164 @@
+package vrrp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/routeros.v2"
+	"gopkg.in/routeros.v2/proto"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
+	"github.com/psolru/mikrotik-exporter/metrics"
+	"github.com/psolru/mikrotik-exporter/routeros/mocks"
+)
+
+func Test_vrrpCollector_Name(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	r.Equal("vrrp", c.Name())
+}
+
+func Test_vrrpCollector_Describe(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	ch := make(chan *prometheus.Desc)
+	done := make(chan struct{})
+	var got []*prometheus.Desc
+	go func() {
+		defer close(done)
+		for desc := range ch {
+			got = append(got, desc)
+		}
+	}()
+
+	c.Describe(ch)
+	close(ch)
+
+	<-done
+	r.ElementsMatch([]*prometheus.Desc{
+		metrics.BuildMetricDescription(prefix, "state", "vrrp instance state (current state = 1)", append(labelNames, "state")),
+		metrics.BuildMetricDescription(prefix, "running", "vrrp instance running (running = 1)", labelNames),
+	}, got)
+}
+
+func Test_vrrpCollector_Collect(t *testing.T) {
+	r := require.New(t)
+
+	c := NewCollector()
+
+	routerOSClientMock := mocks.NewClientMock(t)
+	resetMocks := func() {
+		routerOSClientMock = mocks.NewClientMock(t)
+	}
+
+	stateDesc := metrics.BuildMetricDescription(prefix, "state", "vrrp instance state (current state = 1)", append(labelNames, "state"))
+	runningDesc := metrics.BuildMetricDescription(prefix, "running", "vrrp instance running (running = 1)", labelNames)
+
+	testCases := []struct {
+		name     string
+		setMocks func()
+		want     []prometheus.Metric
+		errWant  string
+	}{
+		{
+			name: "success",
+			setMocks: func() {
+				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
+					r.Equal([]string{
+						"/interface/vrrp/print",
+						"?disabled=false",
+						"=.proplist=name,interface,vrid,priority,master,backup,running",
+					}, sentence)
+				}).Return(&routeros.Reply{
+					Re: []*proto.Sentence{
+						{
+							Map: map[string]string{
+								"name":      "vrrp1",
+								"interface": "bridge",
+								"vrid":      "1",
+								"priority":  "100",
+								"master":    "true",
+								"backup":    "false",
+								"running":   "true",
+							},
+						},
+						{
+							Map: map[string]string{
+								"name":      "vrrp2",
+								"interface": "bridge",
+								"vrid":      "2",
+								"priority":  "50",
+								"master":    "false",
+								"backup":    "false",
+								"running":   "false",
+							},
+						},
+					},
+				}, nil)
+			},
+			want: []prometheus.Metric{
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, "device", "address", "vrrp1", "bridge", "1", "100", "master"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "vrrp1", "bridge", "1", "100", "backup"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "vrrp1", "bridge", "1", "100", "init"),
+				prometheus.MustNewConstMetric(runningDesc, prometheus.GaugeValue, 1, "device", "address", "vrrp1", "bridge", "1", "100"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "vrrp2", "bridge", "2", "50", "master"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 0, "device", "address", "vrrp2", "bridge", "2", "50", "backup"),
+				prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, "device", "address", "vrrp2", "bridge", "2", "50", "init"),
+				prometheus.MustNewConstMetric(runningDesc, prometheus.GaugeValue, 0, "device", "address", "vrrp2", "bridge", "2", "50"),
+			},
+		},
+		{
+			name: "fetch error",
+			setMocks: func() {
+				routerOSClientMock.RunMock.Inspect(func(sentence ...string) {
+					r.Equal([]string{
+						"/interface/vrrp/print",
+						"?disabled=false",
+						"=.proplist=name,interface,vrid,priority,master,backup,running",
+					}, sentence)
+				}).Return(nil, errors.New("some fetch error"))
+			},
+			errWant: "failed to fetch vrrp metrics: some fetch error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetMocks()
+			tc.setMocks()
+			defer routerOSClientMock.MinimockFinish()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+			var got []prometheus.Metric
+			go func() {
+				defer close(done)
+				for desc := range ch {
+					got = append(got, desc)
+				}
+			}()
+
+			errGot := c.Collect(&context.Context{
+				RouterOSClient: routerOSClientMock,
+				MetricsChan:    ch,
+				DeviceName:     "device",
+				DeviceAddress:  "address",
+			})
+			close(ch)
+			if len(tc.errWant) != 0 {
+				r.EqualError(errGot, tc.errWant)
+			} else {
+				r.NoError(errGot)
+			}
+
+			<-done
+			r.ElementsMatch(tc.want, got)
+		})
+	}
+}
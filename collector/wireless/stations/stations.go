@@ -71,14 +71,32 @@ var (
 			ValueType: prometheus.CounterValue,
 		},
 	}
+	countMetricDescription = metrics.BuildMetricDescription(prefix, "count", "number of wlan stations", []string{"name", "address", "interface"})
 )
 
 const prefix = "wlan_station"
 
-type wlanStationsCollector struct{}
+type wlanStationsCollector struct {
+	seriesLimit int
+}
+
+// Option - represents a function on wlan stations collector instance
+type Option func(*wlanStationsCollector)
+
+// WithSeriesLimit - exports only station counts when a device has more stations than limit
+func WithSeriesLimit(limit int) Option {
+	return func(c *wlanStationsCollector) {
+		c.seriesLimit = limit
+	}
+}
 
-func NewCollector() *wlanStationsCollector {
-	return &wlanStationsCollector{}
+func NewCollector(opts ...Option) *wlanStationsCollector {
+	c := &wlanStationsCollector{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
 }
 
 func (c *wlanStationsCollector) Name() string {
@@ -89,6 +107,16 @@ func (c *wlanStationsCollector) Describe(ch chan<- *prometheus.Desc) {
 	for _, d := range metricDescriptions {
 		ch <- d.Desc
 	}
+
+	ch <- countMetricDescription
+	if c.seriesLimit > 0 {
+		ch <- metrics.SeriesTruncatedMetricDescription
+	}
+}
+
+// Supported - the legacy wireless menus are only available with the wireless package installed
+func (c *wlanStationsCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayHavePackage("wireless")
 }
 
 func (c *wlanStationsCollector) Collect(ctx *context.Context) error {
@@ -97,6 +125,12 @@ func (c *wlanStationsCollector) Collect(ctx *context.Context) error {
 		return fmt.Errorf("failed to fetch wlan station info: %w", err)
 	}
 
+	c.collectCounts(stats, ctx)
+
+	if metrics.CheckSeriesLimit(ctx, c.Name(), c.seriesLimit, len(stats)) {
+		return nil
+	}
+
 	for _, re := range stats {
 		c.collectForStat(re, ctx)
 	}
@@ -116,6 +150,19 @@ func (c *wlanStationsCollector) fetch(ctx *context.Context) ([]*proto.Sentence,
 	return reply.Re, nil
 }
 
+func (c *wlanStationsCollector) collectCounts(stats []*proto.Sentence, ctx *context.Context) {
+	counts := make(map[string]float64)
+	for _, re := range stats {
+		counts[re.Map["interface"]]++
+	}
+
+	for iface, v := range counts {
+		ctx.MetricsChan <- prometheus.MustNewConstMetric(countMetricDescription, prometheus.GaugeValue, v,
+			ctx.DeviceName, ctx.DeviceAddress, iface,
+		)
+	}
+}
+
 func (c *wlanStationsCollector) collectForStat(re *proto.Sentence, ctx *context.Context) {
 	for p := range metricDescriptions {
 		switch p {
@@ -46,6 +46,11 @@ func (c *w60gInterfaceCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
+// Supported - the legacy wireless menus are only available with the wireless package installed
+func (c *w60gInterfaceCollector) Supported(caps *context.Capabilities) bool {
+	return caps.MayHavePackage("wireless")
+}
+
 func (c *w60gInterfaceCollector) Collect(ctx *context.Context) error {
 	reply, err := ctx.RouterOSClient.Run(
 		"/interface/w60g/print",
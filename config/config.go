@@ -1,13 +1,30 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net/netip"
+	"strings"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 )
 
+// seriesLimitCollectors - names of the collectors supporting series limits, as used by the collector label of the
+// series truncated metric
+var seriesLimitCollectors = []string{"dhcp_lease", "bridge_host", "wlan_station", "capsman_client"}
+
+// builtinMetricPrefixes - metric name prefixes of the built-in collectors, user defined collectors must not use them
+var builtinMetricPrefixes = []string{
+	"bfd_session", "bgp_session", "bonding", "bridge", "bridge_host", "capsman_client", "collector", "conntrack",
+	"dhcp_ipv6", "dhcp_lease", "dhcp_server", "ethernet", "firmware", "health", "hotspot", "interface",
+	"interface_traffic", "ip_pool", "ipsec", "lte", "mpls", "netwatch", "ospf", "ospf_neighbor", "poe", "route_check",
+	"routes", "scrape", "sfp", "switch", "system", "tunnel", "virtual_interface", "vrrp", "w60g_interface",
+	"wireguard_peers", "wlan_interface", "wlan_station",
+}
+
 type (
 	// Config - represents the global configuration of the exporter
 	Config struct {
@@ -17,6 +34,8 @@ type (
 		Client *Client `yaml:"client,omitempty"`
 		// Features - represents app level feature flags, optional
 		Features *Features `yaml:"features,omitempty"`
+		// MetricRelabelConfigs - represents relabel rules applied to metrics of all devices, optional
+		MetricRelabelConfigs []*RelabelConfig `yaml:"metric_relabel_configs,omitempty"`
 	}
 
 	// Features - represents feature flags for the exporter
@@ -25,6 +44,8 @@ type (
 		BGP bool `yaml:"bgp,omitempty"`
 		// DHCP - enables DHCP server metrics collection
 		DHCP bool `yaml:"dhcp,omitempty"`
+		// DHCPWithoutLeases - drops per lease metrics of the DHCP feature, optional
+		DHCPWithoutLeases bool `yaml:"dhcp_without_leases,omitempty"`
 		// DHCPIPv6 - enables DHCP IPv6 server metrics collection
 		DHCPIPv6 bool `yaml:"dhcp_ipv6,omitempty"`
 		// Firmware - enables firmware metrics collection
@@ -49,6 +70,8 @@ type (
 		WLANInterfaces bool `yaml:"wlan,omitempty"`
 		// Ethernet - enables interface ethernet metrics collection
 		Ethernet bool `yaml:"ethernet,omitempty"`
+		// EthernetStats - enables ethernet error and pause frame counters collection, optional
+		EthernetStats bool `yaml:"ethernet_stats,omitempty"`
 		// IPSec - enables IPSec metrics collection
 		IPSec bool `yaml:"ipsec,omitempty"`
 		// OSPFNeighbors - enables OSPF neighbors metrics collection
@@ -63,6 +86,120 @@ type (
 		BridgeHosts bool `yaml:"bridge_hosts,omitempty"`
 		// WireguardPeers - enables wireguard peers metrics collection
 		WireguardPeers bool `yaml:"wireguard_peers,omitempty"`
+		// Hotspot - enables hotspot metrics collection
+		Hotspot bool `yaml:"hotspot,omitempty"`
+		// HotspotUserLabels - enables per-user hotspot metrics with the given labels, optional
+		HotspotUserLabels []string `yaml:"hotspot_user_labels,omitempty"`
+		// VRRP - enables VRRP metrics collection
+		VRRP bool `yaml:"vrrp,omitempty"`
+		// Bonding - enables bonding interfaces metrics collection
+		Bonding bool `yaml:"bonding,omitempty"`
+		// BridgePorts - enables bridge ports and spanning tree metrics collection
+		BridgePorts bool `yaml:"bridge_ports,omitempty"`
+		// SwitchPorts - enables switch chip info and per port counters collection, without VLAN and ACL rule hit counters
+		SwitchPorts bool `yaml:"switch_ports,omitempty"`
+		// VirtualInterfaces - enables VLAN and tunnel interfaces configuration metrics collection
+		VirtualInterfaces bool `yaml:"virtual_interfaces,omitempty"`
+		// Tunnels - enables GRE, EoIP and IPIP tunnels status metrics collection
+		Tunnels bool `yaml:"tunnels,omitempty"`
+		// InterfaceWithoutStateLabels - drops disabled, running and slave labels from interface metrics, optional
+		InterfaceWithoutStateLabels bool `yaml:"interface_without_state_labels,omitempty"`
+		// InterfaceTraffic - enables interface real-time traffic rates metrics collection
+		InterfaceTraffic bool `yaml:"interface_traffic,omitempty"`
+		// InterfaceTrafficInterfaces - interfaces to monitor, required if interface traffic is enabled
+		InterfaceTrafficInterfaces []string `yaml:"interface_traffic_interfaces,omitempty"`
+		// InterfaceFilter - limits interface, ethernet, sfp, poe, wlan and lte metrics to matching interfaces, optional
+		InterfaceFilter *InterfaceFilter `yaml:"interface_filter,omitempty"`
+		// SeriesLimits - max number of per entry series by collector (dhcp_lease, bridge_host, wlan_station, capsman_client), optional
+		SeriesLimits map[string]int `yaml:"series_limits,omitempty"`
+		// RoutesProtocols - protocols routes are counted by, defaults to bgp, static, ospf, dynamic, connect and rip, optional
+		RoutesProtocols []string `yaml:"routes_protocols,omitempty"`
+		// RoutesByTable - enables routes counts per routing table, distance and protocol, optional
+		RoutesByTable bool `yaml:"routes_by_table,omitempty"`
+		// RouteChecks - enables presence, gateway, distance and active state metrics of routes to the given prefixes, optional
+		RouteChecks []string `yaml:"route_checks,omitempty"`
+		// OSPF - enables OSPF interfaces and LSA database metrics collection
+		OSPF bool `yaml:"ospf,omitempty"`
+		// BFD - enables BFD sessions metrics collection
+		BFD bool `yaml:"bfd,omitempty"`
+		// MPLS - enables MPLS LDP neighbors and forwarding table metrics collection
+		MPLS bool `yaml:"mpls,omitempty"`
+		// CustomCollectors - represents user defined collectors, optional
+		CustomCollectors []*CustomCollector `yaml:"custom_collectors,omitempty"`
+		// ScriptCollectors - represents collectors exporting the output of RouterOS scripts, optional
+		ScriptCollectors []*ScriptCollector `yaml:"script_collectors,omitempty"`
+	}
+
+	// ScriptCollector - represents a collector running a RouterOS script and parsing its output
+	ScriptCollector struct {
+		// Name - collector name, used as metric prefix
+		Name string `yaml:"name"`
+		// Source - inline script source, either source or script_name is required
+		Source string `yaml:"source,omitempty"`
+		// ScriptName - name of a script from /system/script, either source or script_name is required
+		ScriptName string `yaml:"script_name,omitempty"`
+		// Format - text or json, optional, defaults to text
+		Format string `yaml:"format,omitempty"`
+		// Timeout - max script execution time, optional, defaults to 5s
+		Timeout time.Duration `yaml:"timeout,omitempty"`
+		// AllowExecute - confirms that the script may be executed on the device, required
+		AllowExecute bool `yaml:"allow_execute"`
+	}
+
+	// CustomCollector - represents a user defined collector mapping command properties to metrics
+	CustomCollector struct {
+		// Name - collector name, used as metric prefix
+		Name string `yaml:"name"`
+		// Command - RouterOS command to run, e.g. /system/ntp/client/print
+		Command string `yaml:"command"`
+		// Filters - property values the returned entries have to match, optional
+		Filters map[string]string `yaml:"filters,omitempty"`
+		// Labels - properties exported as labels, optional
+		Labels []*CustomLabel `yaml:"labels,omitempty"`
+		// Metrics - properties exported as metrics
+		Metrics []*CustomMetric `yaml:"metrics"`
+	}
+
+	// CustomLabel - represents a property exported as label
+	CustomLabel struct {
+		// Property - RouterOS property name
+		Property string `yaml:"property"`
+		// Name - label name, optional, defaults to property with dashes replaced by underscores
+		Name string `yaml:"name,omitempty"`
+	}
+
+	// CustomMetric - represents a property exported as metric
+	CustomMetric struct {
+		// Property - RouterOS property name
+		Property string `yaml:"property"`
+		// Name - metric name, optional, defaults to property with dashes replaced by underscores
+		Name string `yaml:"name,omitempty"`
+		// Help - metric help text, optional
+		Help string `yaml:"help,omitempty"`
+		// Type - gauge or counter, optional, defaults to gauge
+		Type string `yaml:"type,omitempty"`
+		// Parser - number, duration, bool, pair_first or pair_second, optional, defaults to number
+		Parser string `yaml:"parser,omitempty"`
+	}
+
+	// InterfaceFilter - represents interface include/exclude rules, regular expressions are unanchored
+	InterfaceFilter struct {
+		// IncludeName - regular expression the interface name has to match, optional
+		IncludeName string `yaml:"include_name,omitempty"`
+		// ExcludeName - regular expression the interface name must not match, optional
+		ExcludeName string `yaml:"exclude_name,omitempty"`
+		// IncludeType - regular expression the interface type has to match, optional
+		IncludeType string `yaml:"include_type,omitempty"`
+		// ExcludeType - regular expression the interface type must not match, optional
+		ExcludeType string `yaml:"exclude_type,omitempty"`
+		// IncludeComment - regular expression the interface comment has to match, optional
+		IncludeComment string `yaml:"include_comment,omitempty"`
+		// ExcludeComment - regular expression the interface comment must not match, optional
+		ExcludeComment string `yaml:"exclude_comment,omitempty"`
+		// SkipDynamic - skips dynamic interfaces, optional
+		SkipDynamic bool `yaml:"skip_dynamic,omitempty"`
+		// SkipDisabled - skips disabled interfaces, optional
+		SkipDisabled bool `yaml:"skip_disabled,omitempty"`
 	}
 
 	// Device - represents a target device configuration
@@ -83,6 +220,24 @@ type (
 		Client *Client `yaml:"client,omitempty"`
 		// Features - represents device level feature flags, optional
 		Features *Features `yaml:"features,omitempty"`
+		// MetricRelabelConfigs - represents device level relabel rules applied after the app level ones, optional
+		MetricRelabelConfigs []*RelabelConfig `yaml:"metric_relabel_configs,omitempty"`
+	}
+
+	// RelabelConfig - represents a Prometheus like metric relabel rule
+	RelabelConfig struct {
+		// SourceLabels - labels whose values are joined and matched against Regex, optional
+		SourceLabels []string `yaml:"source_labels,flow,omitempty"`
+		// Separator - separator used to join source label values, optional, defaults to ";"
+		Separator string `yaml:"separator,omitempty"`
+		// Regex - anchored regular expression, optional, defaults to "(.*)"
+		Regex string `yaml:"regex,omitempty"`
+		// TargetLabel - label to write the replacement to, required for replace action
+		TargetLabel string `yaml:"target_label,omitempty"`
+		// Replacement - replacement with regex group references, optional, defaults to "$1"
+		Replacement string `yaml:"replacement,omitempty"`
+		// Action - one of replace, keep, drop, labeldrop, optional, defaults to replace
+		Action string `yaml:"action,omitempty"`
 	}
 
 	// SrvRecord - represents a SRV DNS record configuration
@@ -124,5 +279,134 @@ func Load(r io.Reader) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal bytes to config: %w", err)
 	}
 
+	if err = cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return &cfg, nil
 }
+
+func (c *Config) validate() error {
+	if err := c.Features.validate(); err != nil {
+		return fmt.Errorf("features: %w", err)
+	}
+
+	for _, d := range c.Devices {
+		if err := d.Features.validate(); err != nil {
+			return fmt.Errorf("device %s features: %w", d.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (f *Features) validate() error {
+	if f == nil {
+		return nil
+	}
+
+	if f.InterfaceTraffic && len(f.InterfaceTrafficInterfaces) == 0 {
+		return errors.New("interface_traffic requires interface_traffic_interfaces")
+	}
+
+	for _, rc := range f.RouteChecks {
+		p, err := netip.ParsePrefix(rc)
+		if err != nil {
+			return fmt.Errorf("invalid route check prefix: %w", err)
+		}
+		// routes are looked up by their dst-address, which RouterOS prints without host bits
+		if p != p.Masked() {
+			return fmt.Errorf("route check prefix %s has host bits set, expected %s", rc, p.Masked())
+		}
+	}
+
+	for name, limit := range f.SeriesLimits {
+		if !contains(seriesLimitCollectors, name) {
+			return fmt.Errorf("unknown series limit collector %q, expected one of %s", name, strings.Join(seriesLimitCollectors, ", "))
+		}
+		if limit < 0 {
+			return fmt.Errorf("negative series limit %d of collector %s", limit, name)
+		}
+	}
+
+	if err := f.validateCustomCollectors(); err != nil {
+		return err
+	}
+
+	return f.validateScriptCollectors()
+}
+
+// validateCustomCollectors - checks that custom collectors don't export the same metric twice, neither among themselves
+// nor with a built-in collector
+func (f *Features) validateCustomCollectors() error {
+	metricNames := make(map[string]string)
+	for _, cc := range f.CustomCollectors {
+		for _, m := range cc.Metrics {
+			name := m.Name
+			if len(name) == 0 {
+				name = strings.ReplaceAll(m.Property, "-", "_")
+			}
+			fqName := cc.Name + "_" + name
+
+			for _, p := range builtinMetricPrefixes {
+				if strings.HasPrefix(fqName, p+"_") {
+					return fmt.Errorf("custom collector %s: metric mikrotik_%s clashes with built-in %s metrics", cc.Name, fqName, p)
+				}
+			}
+
+			if other, ok := metricNames[fqName]; ok {
+				return fmt.Errorf("custom collector %s: metric mikrotik_%s is already exported by custom collector %s", cc.Name, fqName, other)
+			}
+			metricNames[fqName] = cc.Name
+		}
+	}
+
+	return nil
+}
+
+// validateScriptCollectors - checks that script collector names are usable as metric prefix and unique among the user
+// defined collectors, and that script names can be passed to /system script run
+func (f *Features) validateScriptCollectors() error {
+	names := make(map[string]bool, len(f.CustomCollectors)+len(f.ScriptCollectors))
+	for _, cc := range f.CustomCollectors {
+		names[cc.Name] = true
+	}
+
+	for _, sc := range f.ScriptCollectors {
+		if !model.IsValidMetricName(model.LabelValue("mikrotik_" + sc.Name)) {
+			return fmt.Errorf("invalid script collector name %q", sc.Name)
+		}
+
+		for _, p := range builtinMetricPrefixes {
+			if sc.Name == p || strings.HasPrefix(sc.Name, p+"_") {
+				return fmt.Errorf("script collector %s: name clashes with built-in %s metrics", sc.Name, p)
+			}
+		}
+
+		if names[sc.Name] {
+			return fmt.Errorf("script collector %s: name is already used by another collector", sc.Name)
+		}
+		names[sc.Name] = true
+
+		if strings.IndexFunc(sc.ScriptName, isInvalidScriptNameRune) != -1 {
+			return fmt.Errorf("script collector %s: invalid script name %q", sc.Name, sc.ScriptName)
+		}
+	}
+
+	return nil
+}
+
+// isInvalidScriptNameRune - reports whether r can't be passed in a quoted RouterOS string as is
+func isInvalidScriptNameRune(r rune) bool {
+	return r < ' ' || r > '~' || r == '"' || r == '\\' || r == '$'
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
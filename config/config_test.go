@@ -40,16 +40,38 @@ func TestShouldParse(t *testing.T) {
 					Address: "1.1.1.1",
 				},
 			},
+			MetricRelabelConfigs: []*RelabelConfig{
+				{
+					SourceLabels: []string{"__name__"},
+					Regex:        "mikrotik_dhcp_lease_expires_after",
+					Action:       "drop",
+				},
+			},
 		}, cfg.Devices[1])
+		r.Equal([]*RelabelConfig{
+			{
+				Regex:  "comment",
+				Action: "labeldrop",
+			},
+			{
+				SourceLabels: []string{"name", "interface"},
+				Separator:    "/",
+				Regex:        "(.+)/(.+)",
+				TargetLabel:  "port",
+				Replacement:  "$1:$2",
+			},
+		}, cfg.MetricRelabelConfigs)
 
 		r.True(cfg.Features.BGP)
 		r.True(cfg.Features.DHCP)
+		r.True(cfg.Features.DHCPWithoutLeases)
 		r.True(cfg.Features.DHCPIPv6)
 		r.True(cfg.Features.Firmware)
 		r.True(cfg.Features.Health)
 		r.True(cfg.Features.IPPools)
 		r.True(cfg.Features.Routes)
 		r.True(cfg.Features.Ethernet)
+		r.True(cfg.Features.EthernetStats)
 		r.True(cfg.Features.PoE)
 		r.True(cfg.Features.SFP)
 		r.True(cfg.Features.WLANStations)
@@ -62,6 +84,53 @@ func TestShouldParse(t *testing.T) {
 		r.True(cfg.Features.Conntrack)
 		r.True(cfg.Features.BridgeHosts)
 		r.True(cfg.Features.WireguardPeers)
+		r.True(cfg.Features.Hotspot)
+		r.Equal([]string{"server", "user"}, cfg.Features.HotspotUserLabels)
+		r.True(cfg.Features.VRRP)
+		r.True(cfg.Features.Bonding)
+		r.True(cfg.Features.BridgePorts)
+		r.True(cfg.Features.SwitchPorts)
+		r.True(cfg.Features.VirtualInterfaces)
+		r.True(cfg.Features.Tunnels)
+		r.True(cfg.Features.InterfaceWithoutStateLabels)
+		r.True(cfg.Features.InterfaceTraffic)
+		r.Equal([]string{"ether1", "ether2"}, cfg.Features.InterfaceTrafficInterfaces)
+		r.Equal(&InterfaceFilter{
+			ExcludeName: "^<.*>$",
+			IncludeType: "^(ether|vlan)$",
+			SkipDynamic: true,
+		}, cfg.Features.InterfaceFilter)
+		r.Equal(map[string]int{"dhcp_lease": 1000, "bridge_host": 5000}, cfg.Features.SeriesLimits)
+		r.Equal([]*CustomCollector{
+			{
+				Name:    "ntp_client",
+				Command: "/system/ntp/client/print",
+				Filters: map[string]string{"enabled": "true"},
+				Labels: []*CustomLabel{
+					{Property: "synced-server", Name: "server"},
+				},
+				Metrics: []*CustomMetric{
+					{Property: "freq-drift", Help: "ntp frequency drift in ppm"},
+					{Property: "system-offset", Name: "offset_seconds", Parser: "duration"},
+					{Property: "packets", Name: "tx_packets", Type: "counter", Parser: "pair_first"},
+				},
+			},
+		}, cfg.Features.CustomCollectors)
+		r.Equal([]*ScriptCollector{
+			{
+				Name:         "wifi",
+				ScriptName:   "wifi-stats",
+				Format:       "json",
+				Timeout:      2 * time.Second,
+				AllowExecute: true,
+			},
+		}, cfg.Features.ScriptCollectors)
+		r.Equal([]string{"bgp", "bgp-vpn", "connect"}, cfg.Features.RoutesProtocols)
+		r.True(cfg.Features.RoutesByTable)
+		r.Equal([]string{"0.0.0.0/0", "::/0"}, cfg.Features.RouteChecks)
+		r.True(cfg.Features.OSPF)
+		r.True(cfg.Features.BFD)
+		r.True(cfg.Features.MPLS)
 	})
 
 	t.Run("invalid yaml", func(t *testing.T) {
@@ -71,6 +140,126 @@ func TestShouldParse(t *testing.T) {
 		r.EqualError(err, "failed to unmarshal bytes to config: yaml: unmarshal errors:\n  line 2: cannot unmarshal !!map into []*config.Device")
 		r.Nil(cfg)
 	})
+
+	t.Run("invalid features", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			config  string
+			errWant string
+		}{
+			{
+				name: "unknown series limit collector",
+				config: `features:
+  series_limits:
+    dhcp: 1000`,
+				errWant: "invalid config: features: unknown series limit collector \"dhcp\", expected one of dhcp_lease, bridge_host, wlan_station, capsman_client",
+			},
+			{
+				name: "interface traffic without interfaces",
+				config: `features:
+  interface_traffic: true`,
+				errWant: "invalid config: features: interface_traffic requires interface_traffic_interfaces",
+			},
+			{
+				name: "invalid route check prefix",
+				config: `features:
+  route_checks: [0.0.0.0/0, default]`,
+				errWant: "invalid config: features: invalid route check prefix: netip.ParsePrefix(\"default\"): no '/'",
+			},
+			{
+				name: "route check prefix with host bits",
+				config: `devices:
+  - name: test1
+    features:
+      route_checks: [10.0.0.1/24]`,
+				errWant: "invalid config: device test1 features: route check prefix 10.0.0.1/24 has host bits set, expected 10.0.0.0/24",
+			},
+			{
+				name: "negative series limit",
+				config: `devices:
+  - name: test1
+    features:
+      series_limits:
+        bridge_host: -1`,
+				errWant: "invalid config: device test1 features: negative series limit -1 of collector bridge_host",
+			},
+			{
+				name: "custom collector metric clashing with built-in collector",
+				config: `features:
+  custom_collectors:
+    - name: ip
+      command: /ip/pool/print
+      metrics:
+        - property: pool-size`,
+				errWant: "invalid config: features: custom collector ip: metric mikrotik_ip_pool_size clashes with built-in ip_pool metrics",
+			},
+			{
+				name: "duplicate custom collector metric",
+				config: `devices:
+  - name: test1
+    features:
+      custom_collectors:
+        - name: ip_cloud
+          command: /ip/cloud/print
+          metrics:
+            - property: status
+        - name: ip
+          command: /ip/cloud/print
+          metrics:
+            - property: cloud-status`,
+				errWant: "invalid config: device test1 features: custom collector ip: metric mikrotik_ip_cloud_status is already exported by custom collector ip_cloud",
+			},
+			{
+				name: "invalid script collector name",
+				config: `features:
+  script_collectors:
+    - name: dns-cache
+      allow_execute: true
+      source: ':put 1'`,
+				errWant: "invalid config: features: invalid script collector name \"dns-cache\"",
+			},
+			{
+				name: "script collector name clashing with custom collector",
+				config: `features:
+  custom_collectors:
+    - name: dns_cache
+      command: /ip/dns/print
+      metrics:
+        - property: cache-used
+  script_collectors:
+    - name: dns_cache
+      allow_execute: true
+      source: ':put 1'`,
+				errWant: "invalid config: features: script collector dns_cache: name is already used by another collector",
+			},
+			{
+				name: "script collector name clashing with built-in collector",
+				config: `features:
+  script_collectors:
+    - name: system
+      allow_execute: true
+      source: ':put 1'`,
+				errWant: "invalid config: features: script collector system: name clashes with built-in system metrics",
+			},
+			{
+				name: "invalid script name",
+				config: `features:
+  script_collectors:
+    - name: wifi
+      allow_execute: true
+      script_name: 'wifi"; /system reboot; "'`,
+				errWant: "invalid config: features: script collector wifi: invalid script name \"wifi\\\"; /system reboot; \\\"\"",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				cfg, err := Load(bytes.NewReader([]byte(tc.config)))
+				r.EqualError(err, tc.errWant)
+				r.Nil(cfg)
+			})
+		}
+	})
 }
 
 func loadTestFile(r *require.Assertions) []byte {
@@ -7,7 +7,10 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"regexp"
 	"time"
+	// embeds the time zone database to resolve device time zones on images without zoneinfo
+	_ "time/tzdata"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -15,30 +18,46 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/psolru/mikrotik-exporter/collector"
+	"github.com/psolru/mikrotik-exporter/collector/bfd"
 	"github.com/psolru/mikrotik-exporter/collector/bgp"
+	"github.com/psolru/mikrotik-exporter/collector/bonding"
 	"github.com/psolru/mikrotik-exporter/collector/bridge_hosts"
+	"github.com/psolru/mikrotik-exporter/collector/bridge_ports"
 	"github.com/psolru/mikrotik-exporter/collector/capsman"
 	"github.com/psolru/mikrotik-exporter/collector/conntrack"
+	"github.com/psolru/mikrotik-exporter/collector/custom"
 	"github.com/psolru/mikrotik-exporter/collector/dhcp"
 	"github.com/psolru/mikrotik-exporter/collector/dhcp_ipv6"
 	"github.com/psolru/mikrotik-exporter/collector/firmware"
 	"github.com/psolru/mikrotik-exporter/collector/health"
+	"github.com/psolru/mikrotik-exporter/collector/hotspot"
 	interface_collector "github.com/psolru/mikrotik-exporter/collector/interface"
 	"github.com/psolru/mikrotik-exporter/collector/interface/ethernet"
+	"github.com/psolru/mikrotik-exporter/collector/interface/filter"
 	"github.com/psolru/mikrotik-exporter/collector/interface/lte"
 	"github.com/psolru/mikrotik-exporter/collector/interface/sfp"
+	"github.com/psolru/mikrotik-exporter/collector/interface/switch_port"
+	"github.com/psolru/mikrotik-exporter/collector/interface/traffic"
+	"github.com/psolru/mikrotik-exporter/collector/interface/tunnel"
+	"github.com/psolru/mikrotik-exporter/collector/interface/virtual"
 	"github.com/psolru/mikrotik-exporter/collector/interface/wlan"
 	"github.com/psolru/mikrotik-exporter/collector/ip_pool"
 	"github.com/psolru/mikrotik-exporter/collector/ipsec"
+	"github.com/psolru/mikrotik-exporter/collector/mpls"
 	"github.com/psolru/mikrotik-exporter/collector/netwatch"
+	"github.com/psolru/mikrotik-exporter/collector/ospf"
 	"github.com/psolru/mikrotik-exporter/collector/ospf_neighbors"
 	"github.com/psolru/mikrotik-exporter/collector/poe"
 	"github.com/psolru/mikrotik-exporter/collector/resource"
+	"github.com/psolru/mikrotik-exporter/collector/route_checks"
 	"github.com/psolru/mikrotik-exporter/collector/routes"
+	"github.com/psolru/mikrotik-exporter/collector/script"
+	"github.com/psolru/mikrotik-exporter/collector/vrrp"
 	"github.com/psolru/mikrotik-exporter/collector/wireguard_peers"
 	"github.com/psolru/mikrotik-exporter/collector/wireless/stations"
 	"github.com/psolru/mikrotik-exporter/collector/wireless/w60g"
 	"github.com/psolru/mikrotik-exporter/config"
+	"github.com/psolru/mikrotik-exporter/relabel"
 )
 
 var (
@@ -56,11 +75,6 @@ var (
 	enableTLS             = flag.Bool("enable-tls", false, "enable TLS to connect to routers")
 	insecureTLSSkipVerify = flag.Bool("insecure-tls-skip-verify", false, "skips verification of server certificate when using TLS (not recommended)")
 
-	defaultCollectors = []collector.FeatureCollector{
-		interface_collector.NewCollector(),
-		resource.NewCollector(),
-	}
-
 	errInvalidParamForSingleDevice = errors.New("missing required param for single device configuration")
 )
 
@@ -179,7 +193,8 @@ func mustCreateMetricsHandler(cfg *config.Config) http.Handler {
 		collectors.NewBuildInfoCollector(),
 		collector.NewMikrotikCollector(
 			buildDevicesFromConfig(cfg),
-			collector.WithCollectors(append(buildCollectors(cfg.Features), defaultCollectors...)...),
+			collector.WithCollectors(append(buildCollectors(cfg.Features), buildDefaultCollectors(cfg.Features)...)...),
+			collector.WithRelabelRules(mustBuildRelabelRules(cfg.MetricRelabelConfigs)...),
 		),
 	)
 
@@ -192,23 +207,116 @@ func mustCreateMetricsHandler(cfg *config.Config) http.Handler {
 	)
 }
 
-func buildCollectors(features *config.Features) []collector.FeatureCollector {
+func buildDefaultCollectors(features *config.Features) []collector.FeatureCollector {
+	return []collector.FeatureCollector{
+		buildInterfaceCollector(features != nil && features.InterfaceWithoutStateLabels, buildInterfaceFilter(features)),
+		resource.NewCollector(),
+	}
+}
+
+// buildDeviceCollectors - builds the device level collectors, a device level interface filter replaces the
+// app level interface, ethernet, sfp, poe, wlan and lte collectors for the device
+func buildDeviceCollectors(appFeatures, deviceFeatures *config.Features) []collector.FeatureCollector {
+	collectors := buildCollectors(deviceFeatures)
+	if deviceFeatures == nil || deviceFeatures.InterfaceFilter == nil {
+		return collectors
+	}
+
+	names := make(map[string]bool, len(collectors))
+	for _, co := range collectors {
+		names[co.Name()] = true
+	}
+
+	interfaceFilter := buildInterfaceFilter(deviceFeatures)
+	withoutStateLabels := deviceFeatures.InterfaceWithoutStateLabels || (appFeatures != nil && appFeatures.InterfaceWithoutStateLabels)
+	filtered := append(
+		[]collector.FeatureCollector{buildInterfaceCollector(withoutStateLabels, interfaceFilter)},
+		buildFilteredCollectors(appFeatures, interfaceFilter)...,
+	)
+	for _, co := range filtered {
+		if !names[co.Name()] {
+			collectors = append(collectors, co)
+		}
+	}
+
+	return collectors
+}
+
+func buildInterfaceCollector(withoutStateLabels bool, f *filter.Filter) collector.FeatureCollector {
+	opts := []interface_collector.Option{
+		interface_collector.WithFilter(f),
+	}
+	if withoutStateLabels {
+		opts = append(opts, interface_collector.WithoutStateLabels())
+	}
+
+	return interface_collector.NewCollector(opts...)
+}
+
+// buildFilteredCollectors - builds the enabled collectors limited by the interface filter besides the interface collector
+func buildFilteredCollectors(features *config.Features, f *filter.Filter) []collector.FeatureCollector {
 	if features == nil {
 		return nil
 	}
 
 	var collectors []collector.FeatureCollector
 
+	if features.PoE {
+		collectors = append(collectors, poe.NewCollector(poe.WithFilter(f)))
+	}
+
+	if features.SFP {
+		collectors = append(collectors, sfp.NewCollector(sfp.WithFilter(f)))
+	}
+
+	if features.WLANInterfaces {
+		collectors = append(collectors, wlan.NewCollector(wlan.WithFilter(f)))
+	}
+
+	if features.Ethernet {
+		opts := []ethernet.Option{ethernet.WithFilter(f)}
+		if features.EthernetStats {
+			opts = append(opts, ethernet.WithStats())
+		}
+
+		collectors = append(collectors, ethernet.NewCollector(opts...))
+	}
+
+	if features.LTE {
+		collectors = append(collectors, lte.NewCollector(lte.WithFilter(f)))
+	}
+
+	return collectors
+}
+
+func buildCollectors(features *config.Features) []collector.FeatureCollector {
+	if features == nil {
+		return nil
+	}
+
+	collectors := buildFilteredCollectors(features, buildInterfaceFilter(features))
+
 	if features.BGP {
 		collectors = append(collectors, bgp.NewCollector())
 	}
 
 	if features.Routes {
-		collectors = append(collectors, routes.NewCollector())
+		var opts []routes.Option
+		if len(features.RoutesProtocols) != 0 {
+			opts = append(opts, routes.WithProtocols(features.RoutesProtocols...))
+		}
+		if features.RoutesByTable {
+			opts = append(opts, routes.WithRoutingTables())
+		}
+		collectors = append(collectors, routes.NewCollector(opts...))
 	}
 
 	if features.DHCP {
-		collectors = append(collectors, dhcp.NewCollector())
+		opts := []dhcp.Option{dhcp.WithSeriesLimit(features.SeriesLimits["dhcp_lease"])}
+		if features.DHCPWithoutLeases {
+			opts = append(opts, dhcp.WithoutLeaseSeries())
+		}
+		collectors = append(collectors, dhcp.NewCollector(opts...))
 	}
 
 	if features.DHCPIPv6 {
@@ -223,36 +331,20 @@ func buildCollectors(features *config.Features) []collector.FeatureCollector {
 		collectors = append(collectors, health.NewCollector())
 	}
 
-	if features.PoE {
-		collectors = append(collectors, poe.NewCollector())
-	}
-
 	if features.IPPools {
 		collectors = append(collectors, ip_pool.NewCollector())
 	}
 
-	if features.SFP {
-		collectors = append(collectors, sfp.NewCollector())
-	}
-
 	if features.W60G {
 		collectors = append(collectors, w60g.NewCollector())
 	}
 
 	if features.WLANStations {
-		collectors = append(collectors, stations.NewCollector())
+		collectors = append(collectors, stations.NewCollector(stations.WithSeriesLimit(features.SeriesLimits["wlan_station"])))
 	}
 
 	if features.CAPsMAN {
-		collectors = append(collectors, capsman.NewCollector())
-	}
-
-	if features.WLANInterfaces {
-		collectors = append(collectors, wlan.NewCollector())
-	}
-
-	if features.Ethernet {
-		collectors = append(collectors, ethernet.NewCollector())
+		collectors = append(collectors, capsman.NewCollector(capsman.WithSeriesLimit(features.SeriesLimits["capsman_client"])))
 	}
 
 	if features.IPSec {
@@ -263,10 +355,6 @@ func buildCollectors(features *config.Features) []collector.FeatureCollector {
 		collectors = append(collectors, ospf_neighbors.NewCollector())
 	}
 
-	if features.LTE {
-		collectors = append(collectors, lte.NewCollector())
-	}
-
 	if features.Netwatch {
 		collectors = append(collectors, netwatch.NewCollector())
 	}
@@ -276,28 +364,176 @@ func buildCollectors(features *config.Features) []collector.FeatureCollector {
 	}
 
 	if features.BridgeHosts {
-		collectors = append(collectors, bridge_hosts.NewCollector())
+		collectors = append(collectors, bridge_hosts.NewCollector(bridge_hosts.WithSeriesLimit(features.SeriesLimits["bridge_host"])))
 	}
 
 	if features.WireguardPeers {
 		collectors = append(collectors, wireguard_peers.NewCollector())
 	}
 
+	if features.Hotspot {
+		collectors = append(collectors, hotspot.NewCollector(hotspot.WithUserLabels(features.HotspotUserLabels...)))
+	}
+
+	if features.VRRP {
+		collectors = append(collectors, vrrp.NewCollector())
+	}
+
+	if features.Bonding {
+		collectors = append(collectors, bonding.NewCollector())
+	}
+
+	if features.BridgePorts {
+		collectors = append(collectors, bridge_ports.NewCollector())
+	}
+
+	if features.SwitchPorts {
+		collectors = append(collectors, switch_port.NewCollector())
+	}
+
+	if features.VirtualInterfaces {
+		collectors = append(collectors, virtual.NewCollector())
+	}
+
+	if features.Tunnels {
+		collectors = append(collectors, tunnel.NewCollector())
+	}
+
+	if features.InterfaceTraffic {
+		collectors = append(collectors, traffic.NewCollector(features.InterfaceTrafficInterfaces...))
+	}
+
+	if len(features.RouteChecks) != 0 {
+		collectors = append(collectors, route_checks.NewCollector(features.RouteChecks...))
+	}
+
+	if features.OSPF {
+		collectors = append(collectors, ospf.NewCollector())
+	}
+
+	if features.BFD {
+		collectors = append(collectors, bfd.NewCollector())
+	}
+
+	if features.MPLS {
+		collectors = append(collectors, mpls.NewCollector())
+	}
+
+	for _, cc := range features.CustomCollectors {
+		collectors = append(collectors, mustBuildCustomCollector(cc))
+	}
+
+	for _, sc := range features.ScriptCollectors {
+		collectors = append(collectors, mustBuildScriptCollector(sc))
+	}
+
 	return collectors
 }
 
+func mustBuildCustomCollector(cc *config.CustomCollector) collector.FeatureCollector {
+	def := &custom.Definition{
+		Name:    cc.Name,
+		Command: cc.Command,
+		Filters: cc.Filters,
+	}
+	for _, l := range cc.Labels {
+		def.Labels = append(def.Labels, &custom.Label{
+			Property: l.Property,
+			Name:     l.Name,
+		})
+	}
+	for _, m := range cc.Metrics {
+		def.Metrics = append(def.Metrics, &custom.Metric{
+			Property: m.Property,
+			Name:     m.Name,
+			Help:     m.Help,
+			Type:     m.Type,
+			Parser:   m.Parser,
+		})
+	}
+
+	c, err := custom.NewCollector(def)
+	if err != nil {
+		log.Fatalf("invalid custom collector config: %v", err)
+	}
+
+	return c
+}
+
+func mustBuildScriptCollector(sc *config.ScriptCollector) collector.FeatureCollector {
+	c, err := script.NewCollector(&script.Definition{
+		Name:         sc.Name,
+		Source:       sc.Source,
+		ScriptName:   sc.ScriptName,
+		Format:       sc.Format,
+		Timeout:      sc.Timeout,
+		AllowExecute: sc.AllowExecute,
+	})
+	if err != nil {
+		log.Fatalf("invalid script collector config: %v", err)
+	}
+
+	return c
+}
+
+func buildInterfaceFilter(features *config.Features) *filter.Filter {
+	if features == nil || features.InterfaceFilter == nil {
+		return nil
+	}
+
+	f := features.InterfaceFilter
+	return &filter.Filter{
+		IncludeName:    mustCompileRegexp(f.IncludeName),
+		ExcludeName:    mustCompileRegexp(f.ExcludeName),
+		IncludeType:    mustCompileRegexp(f.IncludeType),
+		ExcludeType:    mustCompileRegexp(f.ExcludeType),
+		IncludeComment: mustCompileRegexp(f.IncludeComment),
+		ExcludeComment: mustCompileRegexp(f.ExcludeComment),
+		SkipDynamic:    f.SkipDynamic,
+		SkipDisabled:   f.SkipDisabled,
+	}
+}
+
+func mustCompileRegexp(expr string) *regexp.Regexp {
+	if len(expr) == 0 {
+		return nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		log.Fatalf("invalid interface filter expression %q: %v", expr, err)
+	}
+
+	return re
+}
+
+func mustBuildRelabelRules(cfgs []*config.RelabelConfig) []*relabel.Rule {
+	rules := make([]*relabel.Rule, 0, len(cfgs))
+	for _, c := range cfgs {
+		rule, err := relabel.NewRule(c.Action, c.SourceLabels, c.Separator, c.Regex, c.TargetLabel, c.Replacement)
+		if err != nil {
+			log.Fatalf("invalid metric relabel config: %v", err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
 func buildDevicesFromConfig(cfg *config.Config) []*collector.Device {
 	res := make([]*collector.Device, 0, len(cfg.Devices))
 	for _, d := range cfg.Devices {
 		res = append(res, &collector.Device{
-			Name:       d.Name,
-			Address:    d.Address,
-			Port:       d.Port,
-			Username:   d.Username,
-			Password:   d.Password,
-			Client:     buildClient(cfg.Client, d.Client),
-			DNSRecord:  buildDNSRecord(d),
-			Collectors: buildCollectors(d.Features),
+			Name:         d.Name,
+			Address:      d.Address,
+			Port:         d.Port,
+			Username:     d.Username,
+			Password:     d.Password,
+			Client:       buildClient(cfg.Client, d.Client),
+			DNSRecord:    buildDNSRecord(d),
+			Collectors:   buildDeviceCollectors(cfg.Features, d.Features),
+			RelabelRules: mustBuildRelabelRules(d.MetricRelabelConfigs),
 		})
 	}
 	return res
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/psolru/mikrotik-exporter/collector"
+	"github.com/psolru/mikrotik-exporter/collector/interface/ethernet"
+	"github.com/psolru/mikrotik-exporter/collector/interface/sfp"
+	"github.com/psolru/mikrotik-exporter/collector/ip_pool"
+	"github.com/psolru/mikrotik-exporter/config"
+)
+
+func Test_buildDeviceCollectors(t *testing.T) {
+	r := require.New(t)
+
+	appFeatures := &config.Features{
+		Ethernet:      true,
+		EthernetStats: true,
+		SFP:           true,
+		IPPools:       true,
+		InterfaceFilter: &config.InterfaceFilter{
+			ExcludeType: "^pppoe-in$",
+		},
+	}
+
+	r.Empty(buildDeviceCollectors(appFeatures, nil))
+	r.Equal(
+		[]collector.FeatureCollector{ip_pool.NewCollector()},
+		buildDeviceCollectors(appFeatures, &config.Features{IPPools: true}),
+	)
+
+	deviceFeatures := &config.Features{
+		SFP:                         true,
+		InterfaceWithoutStateLabels: true,
+		InterfaceFilter: &config.InterfaceFilter{
+			IncludeName: "^ether",
+		},
+	}
+	deviceFilter := buildInterfaceFilter(deviceFeatures)
+
+	got := buildDeviceCollectors(appFeatures, deviceFeatures)
+	r.Equal([]collector.FeatureCollector{
+		sfp.NewCollector(sfp.WithFilter(deviceFilter)),
+		buildInterfaceCollector(true, deviceFilter),
+		ethernet.NewCollector(ethernet.WithFilter(deviceFilter), ethernet.WithStats()),
+	}, got)
+	r.NotEqual(ethernet.NewCollector(ethernet.WithFilter(buildInterfaceFilter(appFeatures)), ethernet.WithStats()), got[2])
+}
@@ -1,7 +1,12 @@
 package metrics
 
 import (
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/psolru/mikrotik-exporter/collector/context"
 )
 
 // MetricDescription - represents prometheus metric description and value type
@@ -10,11 +15,66 @@ type MetricDescription struct {
 	ValueType prometheus.ValueType
 }
 
+type descriptionInfo struct {
+	name string
+	help string
+}
+
+// descriptions - keeps name and help of the built metric descriptions by their string representation,
+// as prometheus.Desc doesn't expose them otherwise
+var descriptions sync.Map
+
 func BuildMetricDescription(prefix, name, helpText string, labelNames []string) *prometheus.Desc {
-	return prometheus.NewDesc(
-		prometheus.BuildFQName("mikrotik", prefix, name),
+	fqName := prometheus.BuildFQName("mikrotik", prefix, name)
+	desc := prometheus.NewDesc(
+		fqName,
 		helpText,
 		labelNames,
 		nil,
 	)
+	descriptions.Store(desc.String(), descriptionInfo{name: fqName, help: helpText})
+
+	return desc
+}
+
+// DescriptionInfo - returns name and help of a metric description built by BuildMetricDescription
+func DescriptionInfo(desc *prometheus.Desc) (name, help string, ok bool) {
+	v, ok := descriptions.Load(desc.String())
+	if !ok {
+		return "", "", false
+	}
+
+	info := v.(descriptionInfo)
+	return info.name, info.help, true
+}
+
+// SeriesTruncatedMetricDescription - describes the metric exported by collectors with a series limit
+var SeriesTruncatedMetricDescription = BuildMetricDescription("collector", "series_truncated",
+	"whether per entry series were dropped because of the collector series limit (truncated = 1)",
+	[]string{"name", "address", "collector"},
+)
+
+// CheckSeriesLimit - reports whether the number of entries exceeds the collector series limit and exports
+// the series truncated metric, a limit of 0 disables the check
+func CheckSeriesLimit(ctx *context.Context, collector string, limit, entries int) bool {
+	if limit <= 0 {
+		return false
+	}
+
+	var v float64
+	if entries > limit {
+		v = 1
+		log.WithFields(log.Fields{
+			"collector": collector,
+			"device":    ctx.DeviceName,
+			"limit":     limit,
+			"entries":   entries,
+		}).Warn("series limit exceeded, exporting aggregates only")
+	}
+
+	ctx.MetricsChan <- prometheus.MustNewConstMetric(SeriesTruncatedMetricDescription, prometheus.GaugeValue, v,
+		ctx.DeviceName, ctx.DeviceAddress, collector,
+	)
+
+	return v == 1
 }
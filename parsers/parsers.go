@@ -2,7 +2,9 @@ package parsers
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"net/netip"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,15 +14,19 @@ import (
 )
 
 var (
-	durationRegex     = regexp.MustCompile(`(?:(\d*)w)?(?:(\d*)d)?(?:(\d*)h)?(?:(\d*)m)?(?:(\d*)s)?(?:(\d*)ms)?`)
-	durationParts     = [6]time.Duration{time.Hour * 168, time.Hour * 24, time.Hour, time.Minute, time.Second, time.Millisecond}
+	durationRegex = regexp.MustCompile(`(?:(\d*)w)?(?:(\d*)d)?(?:(\d*)h)?(?:(\d*)m)?(?:(\d*)s)?`)
+	durationParts = [5]time.Duration{time.Hour * 168, time.Hour * 24, time.Hour, time.Minute, time.Second}
+	// millisecondsRegex - milliseconds are cut off before matching durationRegex,
+	// which would otherwise parse e.g. 200ms as 200 minutes followed by an empty seconds part
+	millisecondsRegex = regexp.MustCompile(`(\d+)ms$`)
 	wirelessRateRegex = regexp.MustCompile(`([\d.]+)Mbps.*`)
 
 	errUnexpectedPartsCount  = errors.New("unexpected parts count after split")
 	errUnexpectedRegexResult = errors.New("unexpected regex result")
 )
 
-const datetimeFormat = "Jan/02/2006 15:04:05"
+// datetimeFormats - datetime formats used by RouterOS v6 and v7
+var datetimeFormats = []string{"Jan/02/2006 15:04:05", "2006-01-02 15:04:05"}
 
 func ParseCommaSeparatedValuesToFloat64(metric string) (float64, float64, error) {
 	strs := strings.Split(metric, ",")
@@ -42,6 +48,17 @@ func ParseCommaSeparatedValuesToFloat64(metric string) (float64, float64, error)
 }
 
 func ParseDuration(duration string) (float64, error) {
+	var d time.Duration
+	if msMatch := millisecondsRegex.FindStringSubmatch(duration); msMatch != nil {
+		v, err := strconv.Atoi(msMatch[1])
+		if err != nil {
+			return 0, err
+		}
+
+		d = time.Duration(v) * time.Millisecond
+		duration = strings.TrimSuffix(duration, msMatch[0])
+	}
+
 	reMatch := durationRegex.FindAllStringSubmatch(duration, -1)
 
 	// should get one and only one match back on the regex
@@ -49,7 +66,6 @@ func ParseDuration(duration string) (float64, error) {
 		return 0, errUnexpectedRegexResult
 	}
 
-	var d time.Duration
 	for i, match := range reMatch[0] {
 		if len(match) == 0 || i == 0 {
 			continue
@@ -71,18 +87,29 @@ func ParseDuration(duration string) (float64, error) {
 	return d.Seconds(), nil
 }
 
+// ParseDatetime - parses a RouterOS datetime as UTC, see ParseDatetimeInLocation for device local datetimes
 func ParseDatetime(datetime string) (time.Time, error) {
-	t, err := time.Parse(datetimeFormat, datetime)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"datetime": datetime,
-			"value":    t,
-			"error":    err,
-		}).Error("failed to parse datetime field value")
-		return time.Time{}, err
+	return ParseDatetimeInLocation(datetime, time.UTC)
+}
+
+// ParseDatetimeInLocation - parses a RouterOS datetime in the time zone of the device clock
+func ParseDatetimeInLocation(datetime string, loc *time.Location) (time.Time, error) {
+	var (
+		t   time.Time
+		err error
+	)
+	for _, f := range datetimeFormats {
+		if t, err = time.ParseInLocation(f, datetime, loc); err == nil {
+			return t, nil
+		}
 	}
 
-	return t, nil
+	log.WithFields(log.Fields{
+		"datetime": datetime,
+		"value":    t,
+		"error":    err,
+	}).Error("failed to parse datetime field value")
+	return time.Time{}, err
 }
 
 func ParseWirelessRate(rate string) (float64, error) {
@@ -109,3 +136,116 @@ func ParseWirelessRate(rate string) (float64, error) {
 
 	return v, nil
 }
+
+// IPRange - represents an inclusive range of IPv4 addresses
+type IPRange struct {
+	From uint32
+	To   uint32
+}
+
+// IPRanges - represents IPv4 pool ranges
+type IPRanges []IPRange
+
+// Size - returns the number of addresses in the ranges
+func (r IPRanges) Size() float64 {
+	var size float64
+	for _, ir := range r {
+		size += float64(ir.To-ir.From) + 1
+	}
+
+	return size
+}
+
+// Contains - reports whether the IPv4 address is within one of the ranges, invalid addresses are never contained
+func (r IPRanges) Contains(addr string) bool {
+	v, err := parseIPv4ToUint32(addr)
+	if err != nil {
+		return false
+	}
+
+	for _, ir := range r {
+		if v >= ir.From && v <= ir.To {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseIPRanges - parses IPv4 pool ranges, e.g. 10.0.0.10-10.0.0.100,10.0.1.0/24,10.0.2.1
+func ParseIPRanges(ranges string) (IPRanges, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	var res IPRanges
+	for _, r := range strings.Split(ranges, ",") {
+		r = strings.TrimSpace(r)
+
+		switch {
+		case strings.Contains(r, "-"):
+			bounds := strings.SplitN(r, "-", 2)
+			from, err := parseIPv4ToUint32(bounds[0])
+			if err != nil {
+				return nil, err
+			}
+			to, err := parseIPv4ToUint32(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+			if to < from {
+				return nil, fmt.Errorf("invalid range %q", r)
+			}
+
+			res = append(res, IPRange{From: from, To: to})
+		case strings.Contains(r, "/"):
+			p, err := netip.ParsePrefix(r)
+			if err != nil {
+				return nil, err
+			}
+			if !p.Addr().Is4() {
+				return nil, fmt.Errorf("%s is not an ipv4 prefix", r)
+			}
+
+			from := ipv4ToUint32(p.Masked().Addr())
+			res = append(res, IPRange{From: from, To: from + uint32(uint64(1)<<(32-p.Bits())-1)})
+		default:
+			v, err := parseIPv4ToUint32(r)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, IPRange{From: v, To: v})
+		}
+	}
+
+	return res, nil
+}
+
+// ParseIPRangesSize - returns the number of addresses in IPv4 pool ranges,
+// e.g. 10.0.0.10-10.0.0.100,10.0.1.0/24,10.0.2.1
+func ParseIPRangesSize(ranges string) (float64, error) {
+	r, err := ParseIPRanges(ranges)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Size(), nil
+}
+
+func parseIPv4ToUint32(s string) (uint32, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if !addr.Is4() {
+		return 0, fmt.Errorf("%s is not an ipv4 address", s)
+	}
+
+	return ipv4ToUint32(addr), nil
+}
+
+func ipv4ToUint32(addr netip.Addr) uint32 {
+	b := addr.As4()
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
@@ -85,6 +85,29 @@ func TestSplitStringToFloats(t *testing.T) {
 	}
 }
 
+func TestParseDuration_milliseconds(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		input  string
+		output float64
+	}{
+		{"200ms", 0.2},
+		{"0ms", 0},
+		{"1s500ms", 1.5},
+		{"2m3s10ms", 123.01},
+		{"1h5m", 3900},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseDuration(tc.input)
+			r.NoError(err)
+			r.InDelta(tc.output, got, 1e-9)
+		})
+	}
+}
+
 func TestParseDuration(t *testing.T) {
 	r := require.New(t)
 	t.Parallel()
@@ -221,6 +244,11 @@ func TestParseDatetime(t *testing.T) {
 			time.Date(2019, 10, 5, 16, 34, 15, 0, time.UTC),
 			false,
 		},
+		{
+			"2019-10-05 16:34:15",
+			time.Date(2019, 10, 5, 16, 34, 15, 0, time.UTC),
+			false,
+		},
 		{
 			"oct-05-2019 16:34:15",
 			time.Time{},
@@ -252,3 +280,78 @@ func TestParseDatetime(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDatetimeInLocation(t *testing.T) {
+	r := require.New(t)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	r.NoError(err)
+
+	tt, err := ParseDatetimeInLocation("oct/05/2019 16:34:15", berlin)
+	r.NoError(err)
+	r.Equal(int64(1570286055), tt.Unix())
+
+	tt, err = ParseDatetimeInLocation("2019-12-05 16:34:15", berlin)
+	r.NoError(err)
+	r.Equal(time.Date(2019, 12, 5, 15, 34, 15, 0, time.UTC).Unix(), tt.Unix())
+
+	_, err = ParseDatetimeInLocation("16:34:15", berlin)
+	r.Error(err)
+}
+
+func TestParseIPRangesSize(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		ranges  string
+		want    float64
+		wantErr bool
+	}{
+		{ranges: "", want: 0},
+		{ranges: "192.168.88.10-192.168.88.254", want: 245},
+		{ranges: "10.0.0.0/24, 10.0.1.1", want: 257},
+		{ranges: "10.0.0.10-10.0.0.1", wantErr: true},
+		{ranges: "2001:db8::1", wantErr: true},
+		{ranges: "2001:db8::/64", wantErr: true},
+		{ranges: "10.0.0.0/24,2001:db8::/120", wantErr: true},
+		{ranges: "2001:db8::1-2001:db8::ff", wantErr: true},
+		{ranges: "invalid", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseIPRangesSize(tc.ranges)
+		if tc.wantErr {
+			r.Error(err, tc.ranges)
+			continue
+		}
+
+		r.NoError(err, tc.ranges)
+		r.Equal(tc.want, got, tc.ranges)
+	}
+}
+
+func TestIPRanges_Contains(t *testing.T) {
+	r := require.New(t)
+
+	ranges, err := ParseIPRanges("10.0.0.10-10.0.0.20, 10.0.1.0/30,10.0.2.1")
+	r.NoError(err)
+	r.Equal(float64(16), ranges.Size())
+
+	testCases := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "10.0.0.10", want: true},
+		{addr: "10.0.0.20", want: true},
+		{addr: "10.0.0.21", want: false},
+		{addr: "10.0.1.3", want: true},
+		{addr: "10.0.1.4", want: false},
+		{addr: "10.0.2.1", want: true},
+		{addr: "2001:db8::1", want: false},
+		{addr: "", want: false},
+	}
+
+	for _, tc := range testCases {
+		r.Equal(tc.want, ranges.Contains(tc.addr), tc.addr)
+	}
+}
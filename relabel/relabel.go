@@ -0,0 +1,196 @@
+package relabel
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+// Action - represents the action a relabel rule performs
+type Action string
+
+const (
+	// ActionReplace - sets target label to the replacement if regex matches the source labels
+	ActionReplace Action = "replace"
+	// ActionKeep - drops metrics whose source labels don't match regex
+	ActionKeep Action = "keep"
+	// ActionDrop - drops metrics whose source labels match regex
+	ActionDrop Action = "drop"
+	// ActionLabelDrop - removes labels whose name matches regex
+	ActionLabelDrop Action = "labeldrop"
+
+	// MetricNameLabel - represents the metric name in source and target labels
+	MetricNameLabel = "__name__"
+
+	defaultSeparator   = ";"
+	defaultRegex       = "(.*)"
+	defaultReplacement = "$1"
+)
+
+// Rule - represents a compiled relabel rule
+type Rule struct {
+	action       Action
+	sourceLabels []string
+	separator    string
+	regex        *regexp.Regexp
+	targetLabel  string
+	replacement  string
+}
+
+// NewRule - relabel rule constructor, empty values fall back to the Prometheus defaults
+func NewRule(action string, sourceLabels []string, separator, regex, targetLabel, replacement string) (*Rule, error) {
+	r := &Rule{
+		action:       Action(action),
+		sourceLabels: sourceLabels,
+		separator:    separator,
+		targetLabel:  targetLabel,
+		replacement:  replacement,
+	}
+	if len(r.action) == 0 {
+		r.action = ActionReplace
+	}
+	if len(r.separator) == 0 {
+		r.separator = defaultSeparator
+	}
+	if len(regex) == 0 {
+		regex = defaultRegex
+	}
+	if len(r.replacement) == 0 {
+		r.replacement = defaultReplacement
+	}
+
+	var err error
+	r.regex, err = regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", regex, err)
+	}
+
+	switch r.action {
+	case ActionReplace:
+		if len(r.targetLabel) == 0 {
+			return nil, fmt.Errorf("target label is required for %s action", r.action)
+		}
+		if !model.LabelName(r.targetLabel).IsValid() {
+			return nil, fmt.Errorf("invalid target label %q", r.targetLabel)
+		}
+	case ActionKeep, ActionDrop:
+		if len(r.sourceLabels) == 0 {
+			return nil, fmt.Errorf("source labels are required for %s action", r.action)
+		}
+	case ActionLabelDrop:
+	default:
+		return nil, fmt.Errorf("unknown action %q", r.action)
+	}
+
+	return r, nil
+}
+
+// Apply - applies the rules to the metric in order, returns false if the metric has to be dropped
+func Apply(m prometheus.Metric, rules []*Rule) (prometheus.Metric, bool, error) {
+	if len(rules) == 0 {
+		return m, true, nil
+	}
+
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return nil, false, fmt.Errorf("failed to write metric: %w", err)
+	}
+
+	name, help, ok := metrics.DescriptionInfo(m.Desc())
+	if !ok {
+		return nil, false, fmt.Errorf("unknown metric description %s", m.Desc())
+	}
+
+	labels := make(map[string]string, len(pb.Label)+1)
+	labels[MetricNameLabel] = name
+	for _, lp := range pb.Label {
+		labels[lp.GetName()] = lp.GetValue()
+	}
+
+	for _, r := range rules {
+		if !r.apply(labels) {
+			return nil, false, nil
+		}
+	}
+
+	valueType, value, err := valueOf(&pb)
+	if err != nil {
+		return nil, false, err
+	}
+
+	name = labels[MetricNameLabel]
+	delete(labels, MetricNameLabel)
+
+	labelNames := make([]string, 0, len(labels))
+	for l := range labels {
+		labelNames = append(labelNames, l)
+	}
+	sort.Strings(labelNames)
+
+	labelValues := make([]string, 0, len(labelNames))
+	for _, l := range labelNames {
+		labelValues = append(labelValues, labels[l])
+	}
+
+	res, err := prometheus.NewConstMetric(prometheus.NewDesc(name, help, labelNames, nil), valueType, value, labelValues...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build relabeled metric %s: %w", name, err)
+	}
+
+	return res, true, nil
+}
+
+func (r *Rule) apply(labels map[string]string) bool {
+	values := make([]string, 0, len(r.sourceLabels))
+	for _, l := range r.sourceLabels {
+		values = append(values, labels[l])
+	}
+	value := strings.Join(values, r.separator)
+
+	switch r.action {
+	case ActionKeep:
+		return r.regex.MatchString(value)
+	case ActionDrop:
+		return !r.regex.MatchString(value)
+	case ActionLabelDrop:
+		for l := range labels {
+			if l != MetricNameLabel && r.regex.MatchString(l) {
+				delete(labels, l)
+			}
+		}
+	case ActionReplace:
+		indexes := r.regex.FindStringSubmatchIndex(value)
+		if indexes == nil {
+			return true
+		}
+
+		res := string(r.regex.ExpandString(nil, r.replacement, value, indexes))
+		if len(res) == 0 {
+			delete(labels, r.targetLabel)
+		} else {
+			labels[r.targetLabel] = res
+		}
+	}
+
+	return true
+}
+
+func valueOf(pb *dto.Metric) (prometheus.ValueType, float64, error) {
+	switch {
+	case pb.Gauge != nil:
+		return prometheus.GaugeValue, pb.Gauge.GetValue(), nil
+	case pb.Counter != nil:
+		return prometheus.CounterValue, pb.Counter.GetValue(), nil
+	case pb.Untyped != nil:
+		return prometheus.UntypedValue, pb.Untyped.GetValue(), nil
+	default:
+		return 0, 0, fmt.Errorf("unsupported metric type")
+	}
+}
@@ -0,0 +1,179 @@
+package relabel
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/psolru/mikrotik-exporter/metrics"
+)
+
+func TestNewRule(t *testing.T) {
+	r := require.New(t)
+
+	testCases := []struct {
+		name         string
+		action       string
+		sourceLabels []string
+		regex        string
+		targetLabel  string
+		errWant      string
+	}{
+		{
+			name:         "keep",
+			action:       "keep",
+			sourceLabels: []string{"interface"},
+			regex:        "ether.*",
+		},
+		{
+			name:        "default replace",
+			targetLabel: "site",
+		},
+		{
+			name:    "replace without target label",
+			action:  "replace",
+			errWant: "target label is required for replace action",
+		},
+		{
+			name:        "invalid target label",
+			action:      "replace",
+			targetLabel: "site-name",
+			errWant:     "invalid target label \"site-name\"",
+		},
+		{
+			name:    "drop without source labels",
+			action:  "drop",
+			errWant: "source labels are required for drop action",
+		},
+		{
+			name:    "invalid regex",
+			action:  "labeldrop",
+			regex:   "comment(",
+			errWant: "invalid regex \"comment(\": error parsing regexp: missing closing ): `^(?:comment()$`",
+		},
+		{
+			name:    "unknown action",
+			action:  "hashmod",
+			errWant: "unknown action \"hashmod\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRule(tc.action, tc.sourceLabels, "", tc.regex, tc.targetLabel, "")
+			if len(tc.errWant) != 0 {
+				r.EqualError(err, tc.errWant)
+			} else {
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	r := require.New(t)
+
+	mustNewRule := func(action string, sourceLabels []string, regex, targetLabel, replacement string) *Rule {
+		rule, err := NewRule(action, sourceLabels, "", regex, targetLabel, replacement)
+		r.NoError(err)
+		return rule
+	}
+
+	metric := prometheus.MustNewConstMetric(
+		metrics.BuildMetricDescription("interface", "rx_byte", "number of rx bytes on interface", []string{"name", "address", "interface", "comment"}),
+		prometheus.CounterValue, 100, "router1", "10.0.0.1", "ether1", "uplink",
+	)
+
+	testCases := []struct {
+		name     string
+		rules    []*Rule
+		want     prometheus.Metric
+		keepWant bool
+	}{
+		{
+			name:     "no rules",
+			want:     metric,
+			keepWant: true,
+		},
+		{
+			name: "keep matching metric",
+			rules: []*Rule{
+				mustNewRule("keep", []string{"__name__"}, "mikrotik_interface_.*", "", ""),
+			},
+			want: prometheus.MustNewConstMetric(
+				prometheus.NewDesc("mikrotik_interface_rx_byte", "number of rx bytes on interface", []string{"address", "comment", "interface", "name"}, nil),
+				prometheus.CounterValue, 100, "10.0.0.1", "uplink", "ether1", "router1",
+			),
+			keepWant: true,
+		},
+		{
+			name: "keep not matching metric",
+			rules: []*Rule{
+				mustNewRule("keep", []string{"interface"}, "sfp.*", "", ""),
+			},
+		},
+		{
+			name: "drop matching metric",
+			rules: []*Rule{
+				mustNewRule("drop", []string{"__name__", "interface"}, "mikrotik_interface_rx_byte;ether1", "", ""),
+			},
+		},
+		{
+			name: "labeldrop",
+			rules: []*Rule{
+				mustNewRule("labeldrop", nil, "comment|address", "", ""),
+			},
+			want: prometheus.MustNewConstMetric(
+				prometheus.NewDesc("mikrotik_interface_rx_byte", "number of rx bytes on interface", []string{"interface", "name"}, nil),
+				prometheus.CounterValue, 100, "ether1", "router1",
+			),
+			keepWant: true,
+		},
+		{
+			name: "replace",
+			rules: []*Rule{
+				mustNewRule("replace", []string{"name"}, "router(\\d+)", "site", "site-$1"),
+				mustNewRule("replace", []string{"comment"}, "", "comment", "${1}-link"),
+				mustNewRule("replace", []string{"interface"}, "sfp.*", "interface", "sfp"),
+			},
+			want: prometheus.MustNewConstMetric(
+				prometheus.NewDesc("mikrotik_interface_rx_byte", "number of rx bytes on interface", []string{"address", "comment", "interface", "name", "site"}, nil),
+				prometheus.CounterValue, 100, "10.0.0.1", "uplink-link", "ether1", "router1", "site-1",
+			),
+			keepWant: true,
+		},
+		{
+			name: "replace removes label with empty result",
+			rules: []*Rule{
+				mustNewRule("replace", []string{"address"}, "10\\..*", "address", "$2"),
+			},
+			want: prometheus.MustNewConstMetric(
+				prometheus.NewDesc("mikrotik_interface_rx_byte", "number of rx bytes on interface", []string{"comment", "interface", "name"}, nil),
+				prometheus.CounterValue, 100, "uplink", "ether1", "router1",
+			),
+			keepWant: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, keep, err := Apply(metric, tc.rules)
+			r.NoError(err)
+			r.Equal(tc.keepWant, keep)
+			r.Equal(tc.want, got)
+		})
+	}
+}
+
+func TestApply_unknownDescription(t *testing.T) {
+	r := require.New(t)
+
+	rule, err := NewRule("labeldrop", nil, "", "comment", "", "")
+	r.NoError(err)
+
+	desc := prometheus.NewDesc("mikrotik_interface_tx_byte", "number of tx bytes on interface", []string{"interface"}, nil)
+	_, keep, err := Apply(prometheus.MustNewConstMetric(desc, prometheus.CounterValue, 100, "ether1"), []*Rule{rule})
+	r.EqualError(err, "unknown metric description "+desc.String())
+	r.False(keep)
+}